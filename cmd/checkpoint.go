@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dshelley66/gcp-nuke/pkg/types"
+	"github.com/dshelley66/gcp-nuke/resources"
+	log "github.com/sirupsen/logrus"
+)
+
+var itemStateNames = map[ItemState]string{
+	ItemStateNew:      "new",
+	ItemStatePending:  "pending",
+	ItemStateWaiting:  "waiting",
+	ItemStateFailed:   "failed",
+	ItemStateFiltered: "filtered",
+	ItemStateFinished: "finished",
+	ItemStatePrepared: "prepared",
+}
+
+func (s ItemState) String() string {
+	name, ok := itemStateNames[s]
+	if !ok {
+		return fmt.Sprintf("ItemState(%d)", int(s))
+	}
+	return name
+}
+
+func ParseItemState(name string) (ItemState, error) {
+	for state, stateName := range itemStateNames {
+		if stateName == name {
+			return state, nil
+		}
+	}
+	return ItemStateNew, fmt.Errorf("Unknown item state '%s'", name)
+}
+
+// A Checkpoint is the persisted queue of a run, which allows to continue the
+// run with --resume after it died.
+type Checkpoint struct {
+	Project string           `json:"project"`
+	Items   []CheckpointItem `json:"items"`
+}
+
+// CheckpointItem is the persisted form of an Item.
+type CheckpointItem struct {
+	Type       string           `json:"type"`
+	ID         string           `json:"id,omitempty"`
+	Properties types.Properties `json:"properties,omitempty"`
+	State      string           `json:"state"`
+	Reason     string           `json:"reason,omitempty"`
+	Decision   *FilterDecision  `json:"decision,omitempty"`
+	Operation  string           `json:"operation,omitempty"`
+	Attempts   int              `json:"attempts,omitempty"`
+	Permanent  bool             `json:"permanent,omitempty"`
+}
+
+func NewCheckpoint(project string, queue Queue) *Checkpoint {
+	c := &Checkpoint{
+		Project: project,
+		Items:   make([]CheckpointItem, 0, len(queue)),
+	}
+
+	for _, item := range queue {
+		ci := CheckpointItem{
+			Type:      item.Type,
+			State:     item.State.String(),
+			Reason:    item.Reason,
+			Decision:  item.Decision,
+			Attempts:  item.Attempts,
+			Permanent: item.Permanent,
+		}
+		// A panicking resource is still persisted with its state, only
+		// without its identity.
+		_ = safeCall(func() error {
+			if stringer, ok := item.Resource.(resources.LegacyStringer); ok {
+				ci.ID = stringer.String()
+			}
+			if getter, ok := item.Resource.(resources.ResourcePropertyGetter); ok {
+				ci.Properties = getter.Properties()
+			}
+			if resumer, ok := item.Resource.(resources.OperationResumer); ok {
+				ci.Operation = resumer.OperationName()
+			}
+			return nil
+		})
+		c.Items = append(c.Items, ci)
+	}
+
+	return c
+}
+
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := new(Checkpoint)
+	if err := json.Unmarshal(raw, c); err != nil {
+		return nil, fmt.Errorf("Failed to parse checkpoint file %s: %v", path, err)
+	}
+
+	for _, ci := range c.Items {
+		if _, err := ParseItemState(ci.State); err != nil {
+			return nil, fmt.Errorf("Invalid checkpoint file %s: %v", path, err)
+		}
+	}
+
+	return c, nil
+}
+
+// Save writes the checkpoint to a temporary file first and renames it
+// afterwards, so a crash never leaves a truncated checkpoint behind.
+func (c *Checkpoint) Save(path string) error {
+	raw, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(raw); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// Matches checks whether the checkpoint item describes the same resource like
+// the Item.
+func (ci *CheckpointItem) Matches(item *Item) bool {
+	if ci.Type != item.Type {
+		return false
+	}
+
+	id, ok := itemID(item)
+	if !ok || ci.ID != id {
+		return false
+	}
+
+	matches := false
+	_ = safeCall(func() error {
+		getter, ok := item.Resource.(resources.ResourcePropertyGetter)
+		matches = !ok || len(ci.Properties) == 0 || getter.Properties().Equals(ci.Properties)
+		return nil
+	})
+
+	return matches
+}
+
+// itemID returns the legacy ID of the resource, which is empty for resources
+// without one. It fails, if the resource panics.
+func itemID(item *Item) (string, bool) {
+	id := ""
+	err := safeCall(func() error {
+		if stringer, ok := item.Resource.(resources.LegacyStringer); ok {
+			id = stringer.String()
+		}
+		return nil
+	})
+	return id, err == nil
+}
+
+func checkpointKey(resourceType string, id string) string {
+	return resourceType + "\x00" + id
+}
+
+// Apply restores the state of the freshly scanned queue from the checkpoint.
+// Items with a triggered removal are waited for again instead of removing
+// them a second time, if their operation can be resumed. Items of the
+// checkpoint which were not found by the scan are already gone.
+func (c *Checkpoint) Apply(queue Queue) (resumed int, gone int) {
+	// The items are looked up by type and ID. The properties only decide
+	// between items with the same ID.
+	index := map[string][]int{}
+	for i, ci := range c.Items {
+		key := checkpointKey(ci.Type, ci.ID)
+		index[key] = append(index[key], i)
+	}
+	found := make([]bool, len(c.Items))
+
+	for _, item := range queue {
+		id, ok := itemID(item)
+		if !ok {
+			continue
+		}
+
+		match := -1
+		for _, i := range index[checkpointKey(item.Type, id)] {
+			if !found[i] && c.Items[i].Matches(item) {
+				match = i
+				break
+			}
+		}
+		if match < 0 {
+			continue
+		}
+		found[match] = true
+		ci := &c.Items[match]
+
+		// The filters of the current config take precedence.
+		if item.State != ItemStateNew {
+			continue
+		}
+
+		state, _ := ParseItemState(ci.State)
+		switch state {
+		case ItemStatePending, ItemStateWaiting:
+			err := resumeOperation(item, ci.Operation)
+			if err != nil {
+				// Waiting without the operation would never end, since the
+				// resource still exists. So the removal is triggered again.
+				log.Warnf("Cannot resume the removal of %s, removing it again: %v", item.Type, err)
+				continue
+			}
+			item.State = ItemStateWaiting
+			item.Reason = ""
+			resumed = resumed + 1
+		case ItemStateFailed:
+			// The cause of a failure may have been fixed before resuming,
+			// so the attempts start over and permanent failures are
+			// retried, too.
+			item.State = ItemStateFailed
+			item.Reason = ci.Reason
+			resumed = resumed + 1
+		}
+	}
+
+	for i, ci := range c.Items {
+		if found[i] {
+			continue
+		}
+		switch ci.State {
+		case ItemStateFiltered.String(), ItemStateFinished.String():
+			continue
+		}
+		gone = gone + 1
+	}
+
+	return resumed, gone
+}
+
+// resumeOperation picks up the delete operation of an item by its name.
+func resumeOperation(item *Item, operation string) error {
+	resumer, ok := item.Resource.(resources.OperationResumer)
+	if !ok || operation == "" {
+		return fmt.Errorf("the delete operation is unknown")
+	}
+
+	return safeCall(func() error {
+		client, err := getClient(item.Type)(item.Project)
+		if err != nil {
+			return err
+		}
+		return resumer.ResumeOperation(item.Project, client, operation)
+	})
+}
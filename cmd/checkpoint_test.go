@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
+	"github.com/dshelley66/gcp-nuke/resources"
+)
+
+func TestCheckpointResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	previous := Queue{
+		newTestItem(resources.ResourceTypeVPC, ItemStateNew),
+		newTestItem(resources.ResourceTypeSubnet, ItemStateWaiting),
+		newTestItem(resources.ResourceTypeRoute, ItemStatePending),
+		newTestItem(resources.ResourceTypeFirewall, ItemStateFailed),
+		newTestItem(resources.ResourceTypeRouter, ItemStateFinished),
+	}
+	previous[3].Reason = "resource in use"
+
+	err := NewCheckpoint("test-project", previous).Save(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkpoint, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint.Project != "test-project" {
+		t.Errorf("Wrong project. Want: test-project. Have: %s", checkpoint.Project)
+	}
+
+	// The route is gone since the last run and the subnet got filtered by the
+	// current config.
+	current := Queue{
+		{Resource: previous[0].Resource, Type: previous[0].Type, State: ItemStateNew},
+		{Resource: previous[1].Resource, Type: previous[1].Type, State: ItemStateFiltered},
+		{Resource: previous[3].Resource, Type: previous[3].Type, State: ItemStateNew},
+		{Resource: previous[4].Resource, Type: previous[4].Type, State: ItemStateNew},
+	}
+
+	resumed, gone := checkpoint.Apply(current)
+	if resumed != 1 {
+		t.Errorf("Wrong resumed count. Want: 1. Have: %d", resumed)
+	}
+	if gone != 1 {
+		t.Errorf("Wrong gone count. Want: 1. Have: %d", gone)
+	}
+
+	want := []ItemState{ItemStateNew, ItemStateFiltered, ItemStateFailed, ItemStateNew}
+	for i, item := range current {
+		if item.State != want[i] {
+			t.Errorf("Wrong state of %s. Want: %s. Have: %s", item.Type, want[i], item.State)
+		}
+	}
+	if current[2].Reason != "resource in use" {
+		t.Errorf("Wrong reason. Want: resource in use. Have: %s", current[2].Reason)
+	}
+}
+
+type resumableResource struct {
+	testResource
+	operation string
+	err       error
+	resumed   string
+}
+
+func (r *resumableResource) OperationName() string {
+	return r.operation
+}
+
+func (r *resumableResource) ResumeOperation(project *gcputil.Project, client gcputil.GCPClient, name string) error {
+	r.resumed = name
+	return r.err
+}
+
+func TestCheckpointResumeWaiting(t *testing.T) {
+	getClient = func(string) resources.ResourceClientGetter {
+		return func(*gcputil.Project) (gcputil.GCPClient, error) {
+			return nil, nil
+		}
+	}
+	t.Cleanup(func() { getClient = resources.GetClient })
+
+	resumable := &resumableResource{testResource: testResource{name: "resumable"}, operation: "operation-1"}
+	broken := &resumableResource{testResource: testResource{name: "broken"}, operation: "operation-2",
+		err: errors.New("operation not found")}
+
+	previous := Queue{
+		{Resource: resumable, Type: resources.ResourceTypeSubnet, State: ItemStatePending},
+		{Resource: broken, Type: resources.ResourceTypeSubnet, State: ItemStateWaiting},
+		newTestItem(resources.ResourceTypeRoute, ItemStateWaiting),
+	}
+	current := Queue{
+		{Resource: resumable, Type: previous[0].Type, State: ItemStateNew},
+		{Resource: broken, Type: previous[1].Type, State: ItemStateNew},
+		{Resource: previous[2].Resource, Type: previous[2].Type, State: ItemStateNew},
+	}
+
+	resumed, gone := NewCheckpoint("test-project", previous).Apply(current)
+	if resumed != 1 || gone != 0 {
+		t.Errorf("Wrong counts. Want: 1, 0. Have: %d, %d", resumed, gone)
+	}
+	if resumable.resumed != "operation-1" {
+		t.Errorf("Wrong resumed operation. Want: operation-1. Have: %s", resumable.resumed)
+	}
+
+	// Items whose operation cannot be resumed are removed again, instead of
+	// waiting forever.
+	want := []ItemState{ItemStateWaiting, ItemStateNew, ItemStateNew}
+	for i, item := range current {
+		if item.State != want[i] {
+			t.Errorf("Wrong state of item %d. Want: %s. Have: %s", i, want[i], item.State)
+		}
+	}
+}
+
+func TestCheckpointResumeFailed(t *testing.T) {
+	previous := Queue{
+		newTestItem(resources.ResourceTypeFirewall, ItemStateFailed),
+	}
+	previous[0].Attempts = 4
+	previous[0].Permanent = true
+
+	current := Queue{
+		{Resource: previous[0].Resource, Type: previous[0].Type, State: ItemStateNew},
+	}
+
+	NewCheckpoint("test-project", previous).Apply(current)
+	if current[0].State != ItemStateFailed {
+		t.Errorf("Wrong state. Want: %s. Have: %s", ItemStateFailed, current[0].State)
+	}
+	if current[0].Attempts != 0 || current[0].Permanent {
+		t.Errorf("Wrong retry state. Want: 0, false. Have: %d, %t", current[0].Attempts, current[0].Permanent)
+	}
+}
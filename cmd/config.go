@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dshelley66/gcp-nuke/pkg/config"
+	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
+	"github.com/dshelley66/gcp-nuke/resources"
+	"github.com/spf13/cobra"
+)
+
+func NewConfigCommand(params *NukeParameters, creds *gcputil.Credentials) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "works with the config file",
+	}
+
+	cmd.AddCommand(NewConfigValidateCommand(params, creds))
+	cmd.AddCommand(NewConfigSchemaCommand())
+
+	return cmd
+}
+
+func NewConfigValidateCommand(params *NukeParameters, creds *gcputil.Credentials) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate [config file...]",
+		Short: "checks the config files for mistakes without contacting GCP",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := params.ConfigPaths
+			if len(args) > 0 {
+				paths = args
+			}
+			if len(paths) == 0 {
+				return fmt.Errorf("You have to specify the --config flag or the config file.")
+			}
+			files := strings.Join(paths, ", ")
+
+			cmd.SilenceUsage = true
+
+			checksums := params.ConfigSHA256s
+			if len(args) > 0 {
+				checksums = nil
+			}
+			loader, err := NewConfigLoader(paths, checksums, creds)
+			if err != nil {
+				return err
+			}
+			if creds.Project != "" {
+				loader.Variables[config.VariableProjectID] = creds.Project
+			}
+
+			problems, err := loader.Validate(NewCatalog(), paths...)
+			if err != nil {
+				return fmt.Errorf("Failed to parse config file %s: %w", files, err)
+			}
+
+			errors := 0
+			for _, problem := range problems {
+				fmt.Println(problem)
+				if !problem.Warning {
+					errors = errors + 1
+				}
+			}
+			if errors > 0 {
+				return fmt.Errorf("Found %d problems in config file %s.", errors, files)
+			}
+
+			fmt.Printf("The config file %s is valid.\n", files)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// ConfigFetchTimeout limits fetching a single remote config file.
+const ConfigFetchTimeout = time.Minute
+
+// NewConfigLoader returns a loader which fetches remote config files with the
+// credentials of the run. The checksums pin the given paths in the same
+// order.
+func NewConfigLoader(paths []string, checksums []string, creds *gcputil.Credentials) (*config.Loader, error) {
+	loader := &config.Loader{
+		Variables: config.Variables{},
+		Checksums: map[string]string{},
+		Fetch: func(location string) ([]byte, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), ConfigFetchTimeout)
+			defer cancel()
+
+			return creds.Fetch(ctx, location)
+		},
+	}
+
+	if len(checksums) > 0 && len(checksums) != len(paths) {
+		return nil, fmt.Errorf("The --config-sha256 flag has to be specified once for every config file.")
+	}
+	for i, checksum := range checksums {
+		if _, err := hex.DecodeString(checksum); err != nil || len(checksum) != 64 {
+			return nil, fmt.Errorf("The value '%s' of --config-sha256 is not a SHA-256 hex string.", checksum)
+		}
+		loader.Checksums[paths[i]] = checksum
+	}
+
+	return loader, nil
+}
+
+func NewConfigSchemaCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "prints a JSON Schema of the config file for editors and linters",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := json.MarshalIndent(config.Schema(NewCatalog()), "", "  ")
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(data))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// NewCatalog describes all registered resource types, so configs can be
+// validated against them.
+func NewCatalog() config.Catalog {
+	catalog := config.Catalog{
+		ResourceTypes: map[string]config.ResourceTypeInfo{},
+		Aliases:       resources.GetAliasMapping(),
+	}
+
+	apis := map[string]bool{}
+	for _, resourceType := range resources.GetListerNames() {
+		info := config.ResourceTypeInfo{}
+
+		prototype := resources.GetPrototype(resourceType)
+		if _, ok := prototype.(resources.LegacyStringer); ok {
+			info.LegacyID = true
+		}
+		if _, ok := prototype.(resources.Preparer); ok {
+			info.Preparer = true
+		}
+		if getter, ok := prototype.(resources.ResourcePropertyGetter); ok {
+			// The prototype is empty, so a careless implementation might
+			// dereference a nil field.
+			_ = safeCall(func() error {
+				for key := range getter.Properties() {
+					info.Properties = append(info.Properties, key)
+				}
+				return nil
+			})
+			sort.Strings(info.Properties)
+		}
+
+		catalog.ResourceTypes[resourceType] = info
+
+		api := resources.GetAPI(resourceType)
+		if api != "" && !apis[api] {
+			apis[api] = true
+			catalog.APIs = append(catalog.APIs, api)
+		}
+	}
+	sort.Strings(catalog.APIs)
+
+	return catalog
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/dshelley66/gcp-nuke/resources"
+)
+
+func TestCatalog(t *testing.T) {
+	catalog := NewCatalog()
+
+	for _, resourceType := range resources.GetListerNames() {
+		if resources.GetPrototype(resourceType) == nil {
+			t.Errorf("The resource type %s has no prototype.", resourceType)
+			continue
+		}
+
+		info, ok := catalog.ResourceTypes[resourceType]
+		if !ok {
+			t.Errorf("The resource type %s is missing in the catalog.", resourceType)
+			continue
+		}
+		if len(info.Properties) == 0 && !info.LegacyID {
+			t.Errorf("The resource type %s cannot be filtered.", resourceType)
+		}
+	}
+
+	if len(catalog.APIs) == 0 {
+		t.Errorf("The catalog contains no APIs.")
+	}
+}
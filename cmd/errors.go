@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+var (
+	// ErrInterrupted is returned when the run got cancelled by SIGINT or
+	// SIGTERM.
+	ErrInterrupted = errors.New("nuke interrupted")
+
+	// ErrMaxDurationExceeded is returned when the run took longer than
+	// allowed by --max-duration.
+	ErrMaxDurationExceeded = errors.New("nuke exceeded the maximum duration")
+
+	// ErrResourcesRemaining is returned when the verification found
+	// nukeable resources after the run.
+	ErrResourcesRemaining = errors.New("nukeable resources remain after the run")
+)
+
+// Exit codes of gcp-nuke. They follow the conventions of the shell and of
+// timeout(1).
+const (
+	ExitCodeError               = -1
+	ExitCodeInterrupted         = 130
+	ExitCodeMaxDurationExceeded = 124
+)
+
+// ExitCode returns the process exit code for an error returned by the root
+// command.
+func ExitCode(err error) int {
+	switch {
+	case errors.Is(err, ErrInterrupted):
+		return ExitCodeInterrupted
+	case errors.Is(err, ErrMaxDurationExceeded):
+		return ExitCodeMaxDurationExceeded
+	default:
+		return ExitCodeError
+	}
+}
+
+// contextError translates the reason why the run context is done into one of
+// the errors above. It returns nil, if the context is not done yet.
+func contextError(ctx context.Context) error {
+	switch ctx.Err() {
+	case nil:
+		return nil
+	case context.DeadlineExceeded:
+		return ErrMaxDurationExceeded
+	default:
+		return ErrInterrupted
+	}
+}
+
+// PanicError is returned for a panic inside of a resource implementation.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v\n\n%s", err.Value, err.Stack)
+}
+
+// safeCall calls into a resource implementation and turns a panic of any
+// value into a PanicError, so a bug in a single resource does not abort the
+// whole run.
+func safeCall(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	return fn()
+}
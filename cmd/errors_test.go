@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContextExitCode(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	expired, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-expired.Done()
+
+	cases := []struct {
+		name string
+		ctx  context.Context
+		code int
+	}{
+		{name: "Interrupted", ctx: cancelled, code: ExitCodeInterrupted},
+		{name: "MaxDuration", ctx: expired, code: ExitCodeMaxDurationExceeded},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Sleep(tc.ctx, time.Hour)
+			if err == nil {
+				t.Fatal("Expected an error but didn't get one.")
+			}
+
+			if code := ExitCode(fmt.Errorf("wrapped: %w", err)); code != tc.code {
+				t.Fatalf("Wrong exit code. Want: %d. Have: %d", tc.code, code)
+			}
+		})
+	}
+
+	if err := Sleep(context.Background(), time.Millisecond); err != nil {
+		t.Fatalf("Didn't expect an error, but got one: %v", err)
+	}
+
+	if code := ExitCode(fmt.Errorf("failed")); code != ExitCodeError {
+		t.Fatalf("Wrong exit code. Want: %d. Have: %d", ExitCodeError, code)
+	}
+}
+
+type panicResource struct {
+	testResource
+	value interface{}
+}
+
+func (r *panicResource) GetOperationError(context.Context) error {
+	if r.value == nil {
+		var timestamp *string
+		_ = *timestamp
+	}
+	panic(r.value)
+}
+
+func TestPanicIsolation(t *testing.T) {
+	cases := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "String", value: "something broke"},
+		{name: "Error", value: fmt.Errorf("something broke")},
+		{name: "Int", value: 42},
+		{name: "NilPointer", value: nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			item := &Item{
+				Resource: &panicResource{testResource: testResource{name: tc.name}, value: tc.value},
+				State:    ItemStateWaiting,
+				Type:     "Test",
+			}
+
+			new(Nuke).HandleWait(item, newListCache())
+
+			if item.State != ItemStateFailed || !item.Permanent {
+				t.Fatalf("Wrong state. Want: permanently failed. Have: %s (permanent: %t)", item.State, item.Permanent)
+			}
+			if !strings.Contains(item.Reason, "goroutine") {
+				t.Errorf("The reason does not contain the stack trace: %s", item.Reason)
+			}
+		})
+	}
+}
+
+type panicStringResource struct {
+	testResource
+}
+
+func (r *panicStringResource) String() string {
+	panic("something broke")
+}
+
+func TestFindPreviousPanic(t *testing.T) {
+	previous := Queue{
+		{Resource: &panicStringResource{testResource{name: "broken"}}, Type: "Test"},
+	}
+	item := &Item{Resource: &panicStringResource{testResource{name: "broken"}}, Type: "Test"}
+
+	was, err := findPrevious(previous, item)
+	if _, ok := err.(*PanicError); !ok {
+		t.Fatalf("Wrong error. Want: *PanicError. Have: %T", err)
+	}
+	if was != nil {
+		t.Errorf("Unexpected item: %v", was)
+	}
+
+	item = &Item{Resource: &testResource{name: "intact"}, Type: "Test"}
+	previous = append(previous, item)
+	was, err = findPrevious(previous, item)
+	if err != nil {
+		t.Fatalf("Didn't expect an error, but got one: %v", err)
+	}
+	if was != item {
+		t.Errorf("Wrong item. Want: %v. Have: %v", item, was)
+	}
+}
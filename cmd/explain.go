@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dshelley66/gcp-nuke/pkg/config"
+)
+
+// A FilterDecision records which rule filtered an item, so a dry run can be
+// reviewed. It is shown with --explain and kept in the checkpoint.
+type FilterDecision struct {
+	// Rule is the path of the filter in the config, like
+	// presets.terraform.filters.Bucket[0], or the built-in filter of the
+	// resource.
+	Rule     string   `json:"rule"`
+	Preset   string   `json:"preset,omitempty"`
+	Index    int      `json:"index"`
+	Property string   `json:"property,omitempty"`
+	Type     string   `json:"type,omitempty"`
+	Values   []string `json:"values,omitempty"`
+
+	// Value is the value of the property, which matched.
+	Value string `json:"value,omitempty"`
+
+	// Message is the reason given by the built-in filter of the resource.
+	Message string `json:"message,omitempty"`
+}
+
+func newFilterDecision(filter config.Filter, evaluation config.Evaluation) FilterDecision {
+	decision := FilterDecision{
+		Rule:     filter.Origin.Path,
+		Preset:   filter.Origin.Preset,
+		Index:    filter.Origin.Index,
+		Property: filter.Property,
+		Type:     string(filter.Type),
+		Value:    evaluation.Value,
+	}
+
+	switch {
+	case filter.All != nil:
+		decision.Type = "all"
+	case filter.Any != nil:
+		decision.Type = "any"
+	case filter.Not != nil:
+		decision.Type = "not"
+	case filter.Type == config.FilterTypeEmpty:
+		decision.Type = string(config.FilterTypeExact)
+	}
+
+	if !filter.IsGroup() {
+		if filter.Value != "" || len(filter.Values) == 0 {
+			decision.Values = append(decision.Values, filter.Value)
+		}
+		decision.Values = append(decision.Values, filter.Values...)
+	}
+
+	return decision
+}
+
+// builtInRule names the built-in filter of a resource type in decisions and
+// the summary.
+func builtInRule(resourceType string) string {
+	return fmt.Sprintf("built-in filter of %s", resourceType)
+}
+
+func (d FilterDecision) String() string {
+	if d.Preset != "" {
+		return fmt.Sprintf("matched %s (preset '%s')", d.Rule, d.Preset)
+	}
+	return fmt.Sprintf("matched %s", d.Rule)
+}
+
+// A RuleCount is a row of the filter summary.
+type RuleCount struct {
+	Rule  string
+	Count int
+}
+
+// FilterSummary counts the items which each rule filtered. All configured
+// rules are listed, including the ones which matched nothing. Built-in
+// filters are only listed, if they matched.
+func FilterSummary(filters config.Filters, queue Queue) []RuleCount {
+	counts := map[string]int{}
+	for _, list := range filters {
+		for _, filter := range list {
+			counts[filter.Origin.Path] = 0
+		}
+	}
+
+	for _, item := range queue {
+		if item.State != ItemStateFiltered || item.Decision == nil {
+			continue
+		}
+		counts[item.Decision.Rule] = counts[item.Decision.Rule] + 1
+	}
+
+	summary := make([]RuleCount, 0, len(counts))
+	for rule, count := range counts {
+		summary = append(summary, RuleCount{Rule: rule, Count: count})
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		return summary[i].Rule < summary[j].Rule
+	})
+	return summary
+}
+
+// PrintFilterSummary prints how many items each rule filtered.
+func PrintFilterSummary(summary []RuleCount) {
+	unused := 0
+	for _, row := range summary {
+		if row.Count == 0 {
+			unused = unused + 1
+		}
+	}
+
+	fmt.Printf("Filter summary: %d rules, %d matched nothing.\n", len(summary), unused)
+	for _, row := range summary {
+		if row.Count == 0 {
+			ReasonSkip.Printf("    %5d  %s\n", row.Count, row.Rule)
+			continue
+		}
+		fmt.Printf("    %5d  %s\n", row.Count, row.Rule)
+	}
+	fmt.Println()
+}
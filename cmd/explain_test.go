@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/dshelley66/gcp-nuke/pkg/config"
+	"github.com/dshelley66/gcp-nuke/pkg/types"
+)
+
+type selfFilteringResource struct {
+	testResource
+}
+
+func (r *selfFilteringResource) Filter() error {
+	return fmt.Errorf("cannot delete the default network")
+}
+
+func newExplainNuke(t *testing.T) *Nuke {
+	cfg := &config.Nuke{
+		GlobalFilters: config.Filters{
+			"Bucket": {config.NewExactFilter("unused")},
+		},
+		Projects: map[string]config.Project{
+			"sandbox": {Presets: []string{"terraform"}},
+		},
+		Presets: map[string]config.PresetDefinitions{
+			"terraform": {Filters: config.Filters{
+				"Bucket": {
+					config.NewExactFilter("other"),
+					{Type: config.FilterTypeGlob, Value: "tf-*"},
+				},
+			}},
+		},
+	}
+
+	filters, err := cfg.Filters("sandbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Nuke{
+		Parameters: NukeParameters{Explain: true},
+		Config:     cfg,
+		filters:    filters,
+	}
+}
+
+func TestFilterDecision(t *testing.T) {
+	n := newExplainNuke(t)
+
+	item := &Item{Resource: &testResource{name: "tf-state"}, Type: "Bucket"}
+	err := n.Filter(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &FilterDecision{
+		Rule:   "presets.terraform.filters.Bucket[1]",
+		Preset: "terraform",
+		Index:  1,
+		Type:   "glob",
+		Values: []string{"tf-*"},
+		Value:  "tf-state",
+	}
+	if item.State != ItemStateFiltered {
+		t.Fatalf("Wrong state. Want: %v. Have: %v", ItemStateFiltered, item.State)
+	}
+	if !reflect.DeepEqual(item.Decision, want) {
+		t.Errorf("Wrong decision. Want: %+v. Have: %+v", want, item.Decision)
+	}
+
+	explanation := "matched presets.terraform.filters.Bucket[1] (preset 'terraform')\n" +
+		"  ID glob 'tf-*': match (is 'tf-state')"
+	if item.Explanation != explanation {
+		t.Errorf("Wrong explanation. Want: %q. Have: %q", explanation, item.Explanation)
+	}
+
+	item = &Item{Resource: &selfFilteringResource{testResource{name: "default"}}, Type: "VPC"}
+	err = n.Filter(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want = &FilterDecision{
+		Rule:    "built-in filter of VPC",
+		Message: "cannot delete the default network",
+	}
+	if !reflect.DeepEqual(item.Decision, want) {
+		t.Errorf("Wrong decision. Want: %+v. Have: %+v", want, item.Decision)
+	}
+}
+
+func TestFilterWithoutExplain(t *testing.T) {
+	n := newExplainNuke(t)
+	n.Parameters.Explain = false
+
+	item := &Item{Resource: &testResource{name: "tf-state"}, Type: "Bucket"}
+	err := n.Filter(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The decision is always recorded for the checkpoint, but only shown
+	// with --explain.
+	if item.Decision == nil || item.Decision.Rule != "presets.terraform.filters.Bucket[1]" {
+		t.Errorf("Wrong decision: %+v", item.Decision)
+	}
+	if item.Explanation != "" {
+		t.Errorf("Unexpected explanation: %q", item.Explanation)
+	}
+}
+
+func TestFilterSummary(t *testing.T) {
+	n := newExplainNuke(t)
+
+	queue := Queue{
+		{Resource: &testResource{name: "tf-state"}, Type: "Bucket"},
+		{Resource: &testResource{name: "tf-lock"}, Type: "Bucket"},
+		{Resource: &testResource{name: "other"}, Type: "Bucket"},
+		{Resource: &testResource{name: "data"}, Type: "Bucket"},
+		{Resource: &selfFilteringResource{testResource{name: "default"}}, Type: "VPC"},
+	}
+	for _, item := range queue {
+		err := n.Filter(item)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []RuleCount{
+		{Rule: "built-in filter of VPC", Count: 1},
+		{Rule: "filters.Bucket[0]", Count: 0},
+		{Rule: "presets.terraform.filters.Bucket[0]", Count: 1},
+		{Rule: "presets.terraform.filters.Bucket[1]", Count: 2},
+	}
+	have := FilterSummary(n.filters, queue)
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("Wrong summary. Want: %v. Have: %v", want, have)
+	}
+}
+
+func TestCheckpointDecision(t *testing.T) {
+	n := newExplainNuke(t)
+
+	item := &Item{Resource: &testResource{name: "tf-state"}, Type: "Bucket"}
+	err := n.Filter(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkpoint := NewCheckpoint("sandbox", Queue{item})
+	if !reflect.DeepEqual(checkpoint.Items[0].Decision, item.Decision) {
+		t.Errorf("Wrong decision in checkpoint. Want: %+v. Have: %+v", item.Decision, checkpoint.Items[0].Decision)
+	}
+}
+
+type datedResource struct {
+	testResource
+	created string
+}
+
+func (r *datedResource) Properties() types.Properties {
+	properties := types.NewProperties()
+	properties.Set("Name", r.name)
+	properties.Set("CreationDate", r.created)
+	return properties
+}
+
+func TestDateFiltersNuke(t *testing.T) {
+	now := time.Now()
+	dates := map[string]time.Time{
+		"last-hour":  now.Add(-1 * time.Hour),
+		"last-month": now.Add(-30 * 24 * time.Hour),
+		"2022":       time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+	cutoff := now.Add(-7 * 24 * time.Hour).UTC().Format(time.RFC3339)
+
+	// The date types are named after the resources which get nuked.
+	cases := []struct {
+		filter config.Filter
+		nuked  []string
+	}{
+		{config.Filter{Type: config.FilterTypeDateOlderThan, Value: "7d"}, []string{"2022", "last-month"}},
+		{config.Filter{Type: config.FilterTypeDateNewerThan, Value: "7d"}, []string{"last-hour"}},
+		{config.Filter{Type: config.FilterTypeDateBefore, Value: cutoff}, []string{"2022", "last-month"}},
+		{config.Filter{Type: config.FilterTypeDateAfter, Value: cutoff}, []string{"last-hour"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.filter.Type), func(t *testing.T) {
+			tc.filter.Property = "CreationDate"
+			n := &Nuke{filters: config.Filters{"Test": {tc.filter}}}
+
+			nuked := []string{}
+			for name, created := range dates {
+				item := &Item{
+					Resource: &datedResource{testResource: testResource{name: name}, created: created.Format(time.RFC3339)},
+					State:    ItemStateNew,
+					Type:     "Test",
+				}
+				if err := n.Filter(item); err != nil {
+					t.Fatal(err)
+				}
+				if item.State == ItemStateNew {
+					nuked = append(nuked, name)
+				}
+			}
+			sort.Strings(nuked)
+
+			if !reflect.DeepEqual(nuked, tc.nuked) {
+				t.Errorf("Wrong resources nuked. Want: %v. Have: %v", tc.nuked, nuked)
+			}
+		})
+	}
+}
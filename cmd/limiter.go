@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dshelley66/gcp-nuke/resources"
+	"golang.org/x/sync/semaphore"
+)
+
+// removalLimiter runs the removal and wait requests of a queue pass
+// concurrently. The number of requests in flight is bounded globally and
+// optionally per API.
+type removalLimiter struct {
+	global *semaphore.Weighted
+	apis   map[string]*semaphore.Weighted
+	wg     sync.WaitGroup
+}
+
+func newRemovalLimiter(parallelism int, apiLimits map[string]int) *removalLimiter {
+	l := &removalLimiter{
+		global: semaphore.NewWeighted(int64(parallelism)),
+		apis:   map[string]*semaphore.Weighted{},
+	}
+
+	for api, limit := range apiLimits {
+		l.apis[api] = semaphore.NewWeighted(int64(limit))
+	}
+
+	return l
+}
+
+// Go runs fn, if the API of the resource type has capacity left. Otherwise it
+// returns false without running fn, so the item is left for the next pass and
+// a saturated API does not hold up the items of other APIs. Only the global
+// limit blocks, so there is at most one goroutine per request in flight, no
+// matter how long the queue is.
+func (l *removalLimiter) Go(resourceType string, fn func()) bool {
+	api, ok := l.apis[resources.GetAPI(resourceType)]
+	if ok && !api.TryAcquire(1) {
+		return false
+	}
+	l.global.Acquire(context.Background(), 1)
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		defer l.global.Release(1)
+		if ok {
+			defer api.Release(1)
+		}
+
+		fn()
+	}()
+
+	return true
+}
+
+// Wait blocks until all functions started with Go are done.
+func (l *removalLimiter) Wait() {
+	l.wg.Wait()
+}
+
+// listCache caches the listed resources per resource type during a single
+// queue pass, so waiting items of the same type share a single request.
+type listCache struct {
+	mu      sync.Mutex
+	entries map[string]*listCacheEntry
+}
+
+type listCacheEntry struct {
+	once      sync.Once
+	resources []resources.Resource
+	err       error
+}
+
+func newListCache() *listCache {
+	return &listCache{
+		entries: map[string]*listCacheEntry{},
+	}
+}
+
+func (c *listCache) List(item *Item) ([]resources.Resource, error) {
+	key := item.Project.Name + "/" + item.Type
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = new(listCacheEntry)
+		c.entries[key] = entry
+	}
+	c.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.resources, entry.err = item.List()
+	})
+
+	return entry.resources, entry.err
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dshelley66/gcp-nuke/resources"
+)
+
+func TestRemovalLimiter(t *testing.T) {
+	cases := []struct {
+		name        string
+		parallelism int
+		apiLimits   map[string]int
+		want        int
+	}{
+		{
+			name:        "Global",
+			parallelism: 3,
+			want:        3,
+		},
+		{
+			name:        "PerAPI",
+			parallelism: 10,
+			apiLimits:   map[string]int{resources.APICompute: 2},
+			want:        2,
+		},
+		{
+			name:        "OtherAPI",
+			parallelism: 4,
+			apiLimits:   map[string]int{resources.APIStorage: 1},
+			want:        4,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				mu      sync.Mutex
+				running int
+				peak    int
+			)
+
+			limiter := newRemovalLimiter(tc.parallelism, tc.apiLimits)
+			for i := 0; i < 20; i++ {
+				limiter.Go(resources.ResourceTypeVPC, func() {
+					mu.Lock()
+					running = running + 1
+					if running > peak {
+						peak = running
+					}
+					mu.Unlock()
+
+					time.Sleep(10 * time.Millisecond)
+
+					mu.Lock()
+					running = running - 1
+					mu.Unlock()
+				})
+			}
+			limiter.Wait()
+
+			if peak > tc.want {
+				t.Fatalf("Too many parallel calls. Want at most: %d. Have: %d", tc.want, peak)
+			}
+		})
+	}
+}
+
+func TestRemovalLimiterBlocks(t *testing.T) {
+	limiter := newRemovalLimiter(1, nil)
+
+	release := make(chan struct{})
+	limiter.Go(resources.ResourceTypeVPC, func() { <-release })
+
+	// Without capacity left, Go waits instead of starting a goroutine.
+	started := make(chan struct{})
+	go func() {
+		limiter.Go(resources.ResourceTypeVPC, func() {})
+		close(started)
+	}()
+
+	select {
+	case <-started:
+		t.Fatal("Go returned before there was capacity left.")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("Go did not return after the capacity was released.")
+	}
+	limiter.Wait()
+}
+
+func TestRemovalLimiterSaturatedAPI(t *testing.T) {
+	limiter := newRemovalLimiter(4, map[string]int{resources.APICompute: 1})
+
+	release := make(chan struct{})
+	if !limiter.Go(resources.ResourceTypeVPC, func() { <-release }) {
+		t.Fatal("The first request of an API was not started.")
+	}
+
+	// The saturated API must neither block the caller nor the other APIs.
+	returned := make(chan bool)
+	go func() {
+		returned <- limiter.Go(resources.ResourceTypeSubnet, func() {})
+	}()
+	select {
+	case started := <-returned:
+		if started {
+			t.Errorf("A request of the saturated API was started.")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Go blocked on the saturated API.")
+	}
+
+	done := make(chan struct{})
+	if !limiter.Go(resources.ResourceTypeBucket, func() { close(done) }) {
+		t.Fatal("The request of another API was not started.")
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("The request of another API waited for the saturated one.")
+	}
+
+	close(release)
+	limiter.Wait()
+
+	if !limiter.Go(resources.ResourceTypeSubnet, func() {}) {
+		t.Errorf("The API was still saturated after its request finished.")
+	}
+	limiter.Wait()
+}
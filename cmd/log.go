@@ -49,15 +49,31 @@ func Log(project *gcputil.Project, resourceType string, r resources.Resource, c
 
 	rString, ok := r.(resources.LegacyStringer)
 	if ok {
-		ColorResourceID.Print(rString.String())
+		ColorResourceID.Print(describe(rString.String))
 		fmt.Printf(" - ")
 	}
 
 	rProp, ok := r.(resources.ResourcePropertyGetter)
 	if ok {
-		ColorResourceProperties.Print(Sorted(rProp.Properties()))
+		ColorResourceProperties.Print(describe(func() string {
+			return Sorted(rProp.Properties())
+		}))
 		fmt.Printf(" - ")
 	}
 
 	c.Printf("%s\n", msg)
 }
+
+// describe calls a printing method of a resource. A panic inside of it must
+// not abort the run, since the item gets failed anyway.
+func describe(fn func() string) string {
+	var description string
+	err := safeCall(func() error {
+		description = fn()
+		return nil
+	})
+	if err != nil {
+		return "<panic>"
+	}
+	return description
+}
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/dshelley66/gcp-nuke/pkg/config"
@@ -20,9 +22,23 @@ type Nuke struct {
 
 	ResourceTypes types.Collection
 
-	items Queue
+	items      Queue
+	draining   bool
+	retries    *retryBudget
+	scanReport *ScanReport
+
+	featureFlags config.FeatureFlags
+	filters      config.Filters
+	regions      gcputil.Regions
 }
 
+// DrainGracePeriod is the time for which triggered removals are still polled
+// after the run got cancelled.
+const DrainGracePeriod = 30 * time.Second
+
+// PollInterval is the time between two passes over the queue.
+const PollInterval = 5 * time.Second
+
 func NewNuke(params NukeParameters, creds *gcputil.Credentials) *Nuke {
 	n := Nuke{
 		Parameters: params,
@@ -41,10 +57,31 @@ func (n *Nuke) Run() error {
 		}
 	}()
 
+	// A typo in the dependencies of a resource type would otherwise block
+	// its removal forever.
+	err = resources.CheckDependencies()
+	if err != nil {
+		return err
+	}
+
+	for api, limit := range n.Config.RemovalParallelism {
+		if limit < 1 {
+			return fmt.Errorf("The removal parallelism for the API '%s' must be at least 1.", api)
+		}
+	}
+
+	for resourceType, budget := range n.Config.RetryBudgets {
+		if budget < 0 {
+			return fmt.Errorf("The retry budget for the resource type '%s' must not be negative.", resourceType)
+		}
+	}
+	n.retries = newRetryBudget(n.Config.RetryBudgets)
+
 	if n.Parameters.ForceSleep < 3 && n.Parameters.NoDryRun {
 		return fmt.Errorf("Value for --force-sleep cannot be less than 3 seconds if --no-dry-run is set. This is for your own protection.")
 	}
 	forceSleep := time.Duration(n.Parameters.ForceSleep) * time.Second
+	ctx := n.Project.GetContext()
 
 	fmt.Printf("gcp-nuke version %s - %s - %s\n\n", BuildVersion, BuildDate, BuildHash)
 
@@ -53,16 +90,77 @@ func (n *Nuke) Run() error {
 		return err
 	}
 
+	key, err := n.Config.MatchProject(n.Creds.Project)
+	if err != nil {
+		return err
+	}
+	if key != n.Creds.Project {
+		fmt.Printf("Using the config of the project pattern '%s'.\n\n", key)
+	}
+
+	// Former names of resource types keep working in the config, but are
+	// reported once per run.
+	aliases := resources.GetAliasMapping()
+
+	n.featureFlags, err = n.Config.ProjectFeatureFlags(n.Creds.Project)
+	if err != nil {
+		return err
+	}
+	n.featureFlags.DisableDeletionProtection = ResolveFlagAliases(
+		n.featureFlags.DisableDeletionProtection, aliases)
+
+	filters, err := n.Config.Filters(n.Creds.Project)
+	if err != nil {
+		return err
+	}
+	n.filters = ResolveFilterAliases(filters, aliases)
+
+	// Invalid filters, like expressions which do not compile, must stop the
+	// run before anything gets removed.
+	for resourceType, list := range n.filters {
+		for i, filter := range list {
+			err := filter.Validate()
+			if err != nil {
+				return fmt.Errorf("The filter %d of %s is invalid: %v", i+1, resourceType, err)
+			}
+		}
+	}
+
+	accountConfig := n.Config.Projects[key]
+
+	// The cloud-control types of aws-nuke have no equivalent here, so they
+	// are not resolved. Renamed types are handled by the aliases instead.
+	n.ResourceTypes = ResolveResourceTypes(
+		resources.GetListerNames(),
+		map[string]string{},
+		[]types.Collection{
+			ResolveAliases(n.Parameters.Targets, aliases),
+			ResolveAliases(n.Config.ResourceTypes.Targets, aliases),
+			ResolveAliases(accountConfig.ResourceTypes.Targets, aliases),
+		},
+		[]types.Collection{
+			ResolveAliases(n.Parameters.Excludes, aliases),
+			ResolveAliases(n.Config.ResourceTypes.Excludes, aliases),
+			ResolveAliases(accountConfig.ResourceTypes.Excludes, aliases),
+		},
+		[]types.Collection{},
+	)
+	for _, resourceType := range n.ResourceTypes {
+		if resources.GetLister(resourceType) == nil {
+			return fmt.Errorf("The resource type '%s' does not exist.", resourceType)
+		}
+	}
+
 	fmt.Printf("Do you really want to nuke the project with the ID %s?\n", n.Creds.Project)
 	if n.Parameters.Force {
 		fmt.Printf("Waiting %v before continuing.\n", forceSleep)
-		time.Sleep(forceSleep)
+		err = Sleep(ctx, forceSleep)
 	} else {
 		fmt.Printf("Do you want to continue? Enter project ID to continue.\n")
-		err = Prompt(n.Creds.Project)
-		if err != nil {
-			return err
-		}
+		err = Prompt(ctx, n.Creds.Project)
+	}
+	if err != nil {
+		return err
 	}
 
 	err = n.Scan()
@@ -70,7 +168,29 @@ func (n *Nuke) Run() error {
 		return err
 	}
 
-	if n.items.Count(ItemStateNew) == 0 {
+	// The listing requests fail, if the run got cancelled during the scan.
+	// Do not continue with an incomplete list.
+	err = contextError(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = n.scanError()
+	if err != nil {
+		return err
+	}
+
+	if n.Parameters.ResumePath != "" {
+		err = n.Resume()
+		if err != nil {
+			return err
+		}
+	}
+
+	// The checkpoint of a dry run records why each resource was filtered.
+	n.SaveCheckpoint()
+
+	if n.items.Count(ItemStateNew, ItemStateWaiting, ItemStateFailed) == 0 {
 		fmt.Println("No resource to delete.")
 		return nil
 	}
@@ -83,43 +203,82 @@ func (n *Nuke) Run() error {
 	fmt.Printf("Do you really want to nuke these resources on the project with the ID %s?\n", n.Creds.Project)
 	if n.Parameters.Force {
 		fmt.Printf("Waiting %v before continuing.\n", forceSleep)
-		time.Sleep(forceSleep)
+		err = Sleep(ctx, forceSleep)
 	} else {
 		fmt.Printf("Do you want to continue? Enter project ID to continue.\n")
-		err = Prompt(n.Creds.Project)
+		err = Prompt(ctx, n.Creds.Project)
+	}
+	if err != nil {
+		return err
+	}
+
+	for round := 0; ; round++ {
+		err = n.RemoveAll(ctx)
 		if err != nil {
 			return err
 		}
+
+		n.PrintSummary()
+
+		if !n.Parameters.Verify {
+			return nil
+		}
+
+		var remaining int
+		remaining, err = n.Verify()
+		if err != nil {
+			return err
+		}
+		if remaining == 0 {
+			return nil
+		}
+		if round >= n.Parameters.VerifyRetries {
+			return fmt.Errorf("%w: %d resources", ErrResourcesRemaining, remaining)
+		}
+
+		fmt.Printf("Nuking the remaining resources again (%d of %d).\n\n", round+1, n.Parameters.VerifyRetries)
 	}
+}
 
-	failCount := 0
+// RemoveAll handles the queue until all items are removed or nothing can be
+// done about the remaining ones anymore.
+func (n *Nuke) RemoveAll(ctx context.Context) error {
 	waitingCount := 0
 
 	for {
 		n.HandleQueue()
+		n.SaveCheckpoint()
 
-		if n.items.Count(ItemStatePending, ItemStateWaiting, ItemStateNew) == 0 && n.items.Count(ItemStateFailed) > 0 {
-			if failCount >= 2 {
-				log.Errorf("There are resources in failed state, but none are ready for deletion, anymore.")
-				fmt.Println()
+		if err := contextError(ctx); err != nil {
+			return n.Drain(err)
+		}
 
-				for _, item := range n.items {
-					if item.State != ItemStateFailed {
-						continue
-					}
+		if n.items.Count(ItemStatePending, ItemStateWaiting) == 0 && n.items.CountReady() == 0 &&
+			n.items.CountRetryable() == 0 && n.items.Count(ItemStateFailed) > 0 {
+			log.Errorf("There are resources in failed state, but none are ready for deletion, anymore.")
+			fmt.Println()
 
-					item.Print()
-					log.Error(item.Reason)
+			blocked := n.items.Count(ItemStateNew, ItemStatePrepared)
+			for _, item := range n.items {
+				if item.State != ItemStateFailed {
+					continue
+				}
+				if !item.Permanent {
+					blocked = blocked + 1
+					continue
 				}
 
-				return fmt.Errorf("failed")
+				item.Print()
+				log.Error(item.Reason)
 			}
 
-			failCount = failCount + 1
-		} else {
-			failCount = 0
+			if blocked > 0 {
+				log.Errorf("%d resources were not removed, because resources they depend on are in failed state.", blocked)
+			}
+
+			return fmt.Errorf("failed")
 		}
-		if n.Parameters.MaxWaitRetries != 0 && n.items.Count(ItemStateWaiting, ItemStatePending) > 0 && n.items.Count(ItemStateNew) == 0 {
+		if n.Parameters.MaxWaitRetries != 0 && n.items.Count(ItemStateWaiting, ItemStatePending) > 0 && n.items.CountReady() == 0 {
 			if waitingCount >= n.Parameters.MaxWaitRetries {
 				return fmt.Errorf("Max wait retries of %d exceeded", n.Parameters.MaxWaitRetries)
 			}
@@ -127,67 +286,286 @@ func (n *Nuke) Run() error {
 		} else {
 			waitingCount = 0
 		}
-		if n.items.Count(ItemStateNew, ItemStatePending, ItemStateFailed, ItemStateWaiting) == 0 {
+		if n.items.Count(ItemStateNew, ItemStatePrepared, ItemStatePending, ItemStateFailed, ItemStateWaiting) == 0 {
 			break
 		}
 
-		time.Sleep(5 * time.Second)
+		if err := Sleep(ctx, n.nextPoll()); err != nil {
+			return n.Drain(err)
+		}
 	}
 
+	return nil
+}
+
+// Drain is called when the run got cancelled. It stops triggering new
+// removals, but keeps polling the removals which are already in flight for a
+// short grace period. Afterwards it prints the summary and returns the cause.
+func (n *Nuke) Drain(cause error) error {
+	log.Warnf("%v: waiting up to %v for triggered removals to finish.", cause, DrainGracePeriod)
+	fmt.Println()
+
+	// The context of the project is already done, so further requests need a
+	// fresh one.
+	ctx, cancel := context.WithTimeout(context.Background(), DrainGracePeriod)
+	defer cancel()
+	n.Project.SetContext(ctx)
+	n.draining = true
+
+	for n.items.Count(ItemStatePending, ItemStateWaiting) > 0 {
+		n.HandleQueue()
+		n.SaveCheckpoint()
+
+		if Sleep(ctx, PollInterval) != nil {
+			break
+		}
+	}
+
+	fmt.Printf("Nuke interrupted: %d waiting, %d not removed.\n",
+		n.items.Count(ItemStatePending, ItemStateWaiting), n.items.Count(ItemStateNew, ItemStatePrepared))
+	n.PrintSummary()
+
+	return cause
+}
+
+// nextPoll returns the time until the next pass over the queue. If only
+// failed items are left, it waits for the earliest of their retries.
+func (n *Nuke) nextPoll() time.Duration {
+	if n.items.Count(ItemStatePending, ItemStateWaiting) > 0 || n.items.CountReady() > 0 {
+		return PollInterval
+	}
+
+	next := n.items.NextRetry()
+	if delay := time.Until(next); !next.IsZero() && delay > PollInterval {
+		return delay
+	}
+
+	return PollInterval
+}
+
+func (n *Nuke) PrintSummary() {
 	fmt.Printf("Nuke complete: %d failed, %d skipped, %d finished.\n\n",
 		n.items.Count(ItemStateFailed), n.items.Count(ItemStateFiltered), n.items.Count(ItemStateFinished))
+}
+
+func (n *Nuke) Scan() error {
+	queue, report, err := n.scan(true)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Scan complete: %d total, %d nukeable, %d filtered.\n\n",
+		queue.CountTotal(), queue.Count(ItemStateNew), queue.Count(ItemStateFiltered))
+	report.Print()
+
+	if n.Parameters.Explain {
+		PrintFilterSummary(FilterSummary(n.filters, queue))
+	}
+
+	n.items = queue
+	n.scanReport = report
 
 	return nil
 }
 
-func (n *Nuke) Scan() error {
-	accountConfig := n.Config.Projects[n.Creds.Project]
+// scanError fails the run with --fail-on-scan-error, if resource types could
+// not be listed. Types which were skipped, eg because their API is disabled,
+// cannot contain any resources, so they do not count.
+func (n *Nuke) scanError() error {
+	if failed := n.scanReport.Count(ScanFailed); failed > 0 && n.Parameters.FailOnScanError {
+		return fmt.Errorf("Listing of %d resource types failed. The project cannot be considered clean.", failed)
+	}
+	return nil
+}
 
-	resourceTypes := ResolveResourceTypes(
-		resources.GetListerNames(),
-		map[string]string{},
-		[]types.Collection{
-			n.Parameters.Targets,
-			n.Config.ResourceTypes.Targets,
-			accountConfig.ResourceTypes.Targets,
-		},
-		[]types.Collection{
-			n.Parameters.Excludes,
-			n.Config.ResourceTypes.Excludes,
-			accountConfig.ResourceTypes.Excludes,
-		},
-		[]types.Collection{},
-	)
+// Verify scans the project again after the removal and reports the resources
+// which are still nukeable, compared to the queue of the run. Afterwards the
+// queue is replaced by the result of the scan, so it can be nuked again.
+func (n *Nuke) Verify() (int, error) {
+	fmt.Println("Verifying that all resources are removed.")
+	fmt.Println()
 
-	n.Project.Locations = accountConfig.Locations
-	queue := make(Queue, 0)
+	previous := n.items
+	queue, report, err := n.scan(false)
+	if err != nil {
+		return 0, err
+	}
+	if err := contextError(n.Project.GetContext()); err != nil {
+		return 0, err
+	}
 
-	items := Scan(n.Project, resourceTypes)
-	for item := range items {
-		ffGetter, ok := item.Resource.(resources.FeatureFlagGetter)
-		if ok {
-			ffGetter.FeatureFlags(n.Config.FeatureFlags)
+	remaining, appeared := 0, 0
+	for _, item := range queue {
+		if item.State == ItemStateFiltered {
+			continue
+		}
+
+		was, err := findPrevious(previous, item)
+		if err != nil {
+			// The resource is still there, even if it cannot be told apart
+			// from the others.
+			remaining = remaining + 1
+			Log(item.Project, item.Type, item.Resource, ReasonError, fmt.Sprintf("still exists (comparison failed: %v)", err))
+		} else if was == nil {
+			appeared = appeared + 1
+			Log(item.Project, item.Type, item.Resource, ReasonError, "appeared during the run")
+		} else {
+			remaining = remaining + 1
+			Log(item.Project, item.Type, item.Resource, ReasonError, fmt.Sprintf("still exists (was %s)", was.State))
 		}
+	}
+
+	fmt.Printf("Verification complete: %d remaining, %d appeared during the run.\n\n", remaining, appeared)
+	report.Print()
+
+	// Resources of types which could not be listed might remain unnoticed.
+	if failed := report.Count(ScanFailed); failed > 0 {
+		return remaining + appeared, fmt.Errorf("Verification incomplete, because listing of %d resource types failed.", failed)
+	}
 
+	n.items = queue
+	n.scanReport = report
+
+	return remaining + appeared, nil
+}
+
+// findPrevious looks up the item in the queue of the run. The comparison
+// calls String() or Properties() of the resources, so panics are isolated.
+func findPrevious(previous Queue, item *Item) (*Item, error) {
+	var was *Item
+	err := safeCall(func() error {
+		was = previous.Find(item)
+		return nil
+	})
+	return was, err
+}
+
+func (n *Nuke) scan(verbose bool) (Queue, *ScanReport, error) {
+	key, err := n.Config.MatchProject(n.Creds.Project)
+	if err != nil {
+		return nil, nil, err
+	}
+	accountConfig := n.Config.Projects[key]
+
+	// The endpoints are needed to resolve the locations, so they only
+	// depend on the configured locations.
+	services, err := n.Config.CustomEndpoints.Services(accountConfig.Locations)
+	if err != nil {
+		return nil, nil, err
+	}
+	n.Project.Endpoints = map[string]gcputil.Endpoint{}
+	for api, service := range services {
+		n.Project.Endpoints[api] = gcputil.Endpoint{
+			URL:                   service.URL,
+			TLSInsecureSkipVerify: service.TLSInsecureSkipVerify,
+		}
+	}
+
+	n.Project.Locations, err = n.resolveLocations(accountConfig.Locations)
+	if err != nil {
+		return nil, nil, err
+	}
+	n.Project.ScopeStorageLocations = n.featureFlags.StorageLocationsScoped()
+
+	queue := make(Queue, 0)
+
+	items, report := Scan(n.Project, n.ResourceTypes)
+	for item := range items {
 		queue = append(queue, item)
-		err := n.Filter(item)
+
+		// A panic while filtering fails the item, so it does not get removed
+		// without being checked against the filters.
+		var err error
+		panicErr := safeCall(func() error {
+			ffGetter, ok := item.Resource.(resources.FeatureFlagGetter)
+			if ok {
+				ffGetter.FeatureFlags(n.featureFlags)
+			}
+
+			err = n.Filter(item)
+			return nil
+		})
+		if panicErr != nil {
+			item.Fail(panicErr)
+		}
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 
-		if item.State != ItemStateFiltered || !n.Parameters.Quiet {
+		if verbose && (item.State != ItemStateFiltered || !n.Parameters.Quiet) {
 			item.Print()
 		}
 	}
 
-	fmt.Printf("Scan complete: %d total, %d nukeable, %d filtered.\n\n",
-		queue.CountTotal(), queue.Count(ItemStateNew), queue.Count(ItemStateFiltered))
+	return queue, report, nil
+}
 
-	n.items = queue
+// Resume restores the states of the scanned items from the checkpoint file.
+func (n *Nuke) Resume() error {
+	checkpoint, err := LoadCheckpoint(n.Parameters.ResumePath)
+	if err != nil {
+		return err
+	}
+
+	if checkpoint.Project != n.Creds.Project {
+		return fmt.Errorf("The checkpoint file %s belongs to the project '%s' and not to '%s'.",
+			n.Parameters.ResumePath, checkpoint.Project, n.Creds.Project)
+	}
+
+	resumed, gone := checkpoint.Apply(n.items)
+	for _, item := range n.items {
+		if item.State == ItemStateWaiting || item.State == ItemStateFailed {
+			item.Print()
+		}
+	}
+
+	fmt.Printf("Resume complete: %d resumed, %d already removed.\n\n", resumed, gone)
 
 	return nil
 }
 
+// SaveCheckpoint writes the current queue to the checkpoint file, if one is
+// configured. A failed write does not stop the run.
+func (n *Nuke) SaveCheckpoint() {
+	path := n.Parameters.CheckpointPath
+	if path == "" {
+		return
+	}
+
+	err := NewCheckpoint(n.Creds.Project, n.items).Save(path)
+	if err != nil {
+		log.Warnf("Failed to write checkpoint file %s: %v", path, err)
+	}
+}
+
+// resolveLocations expands 'all' and checks the configured locations against
+// the regions of the project. If the regions cannot be listed, eg because the
+// Compute Engine API is disabled, the locations are used as they are, unless
+// 'all' is requested.
+func (n *Nuke) resolveLocations(locations []string) ([]string, error) {
+	if n.regions == nil {
+		options, err := n.Project.ClientOptions(resources.APICompute, gcputil.TransportREST)
+		if err != nil {
+			return nil, err
+		}
+
+		regions, err := gcputil.ListRegions(n.Project.GetContext(), n.Project.Name, options...)
+		if err != nil {
+			for _, location := range locations {
+				if strings.EqualFold(location, gcputil.LocationAll) {
+					return nil, fmt.Errorf("Cannot resolve the location '%s': %w", location, err)
+				}
+			}
+
+			log.Warnf("Cannot check the locations, since the regions could not be listed: %v", err)
+			return locations, nil
+		}
+		n.regions = regions
+	}
+
+	return n.regions.Resolve(locations)
+}
+
 func (n *Nuke) Filter(item *Item) error {
 
 	checker, ok := item.Resource.(resources.Filter)
@@ -196,6 +574,13 @@ func (n *Nuke) Filter(item *Item) error {
 		if err != nil {
 			item.State = ItemStateFiltered
 			item.Reason = err.Error()
+			item.Decision = &FilterDecision{
+				Rule:    builtInRule(item.Type),
+				Message: err.Error(),
+			}
+			if n.Parameters.Explain {
+				item.Explanation = item.Decision.String()
+			}
 
 			// Not returning the error, since it could be because of a failed
 			// request to the API. We do not want to block the whole nuking,
@@ -204,34 +589,31 @@ func (n *Nuke) Filter(item *Item) error {
 		}
 	}
 
-	accountFilters, err := n.Config.Filters(n.Creds.Project)
-	if err != nil {
-		return err
-	}
-
-	itemFilters, ok := accountFilters[item.Type]
+	itemFilters, ok := n.filters[item.Type]
 	if !ok {
 		return nil
 	}
 
 	for _, filter := range itemFilters {
-		prop, err := item.GetProperty(filter.Property)
-		if err != nil {
-			log.Warnf(err.Error())
-			continue
-		}
-		match, err := filter.Match(prop)
+		evaluation, err := filter.Evaluate(item)
 		if err != nil {
 			return err
 		}
-
-		if IsTrue(filter.Invert) {
-			match = !match
+		for _, err := range evaluation.Errors() {
+			log.Warnf(err.Error())
 		}
 
-		if match {
+		if evaluation.Match {
+			decision := newFilterDecision(filter, evaluation)
 			item.State = ItemStateFiltered
-			item.Reason = "filtered by config"
+			item.Reason = filter.Reason()
+			item.Decision = &decision
+			switch {
+			case n.Parameters.Explain:
+				item.Explanation = fmt.Sprintf("%s\n%s", decision, util.Indent(evaluation.String(), "  "))
+			case filter.IsGroup():
+				item.Explanation = evaluation.String()
+			}
 			return nil
 		}
 	}
@@ -240,26 +622,97 @@ func (n *Nuke) Filter(item *Item) error {
 }
 
 func (n *Nuke) HandleQueue() {
-	listCache := make(map[string]map[string][]resources.Resource)
+	var (
+		now         = time.Now()
+		cache       = newListCache()
+		limiter     = newRemovalLimiter(n.Parameters.RemovalParallelism, n.Config.RemovalParallelism)
+		outstanding = n.items.Outstanding()
+		printers    = make([]func(), len(n.items))
+	)
+
+	for i, item := range n.items {
+		item := item
+		started := true
+
+		// The run may get cancelled during the pass. No requests are started
+		// afterwards, since they would fail anyway.
+		if item.Cancelled() {
+			continue
+		}
+
+		// Removals must not be triggered anymore, once the run got cancelled.
+		if n.draining && (item.State == ItemStateNew || item.State == ItemStatePrepared || item.State == ItemStateFailed) {
+			continue
+		}
+
+		// Items are not retried before their backoff passed. Permanently
+		// failed items are not retried at all, only checked for existence.
+		if now.Before(item.RetryAt) && item.State != ItemStateNew && item.State != ItemStatePrepared && !item.Permanent {
+			continue
+		}
 
-	for _, item := range n.items {
 		switch item.State {
-		case ItemStateNew:
-			n.HandleRemove(item)
-			item.Print()
+		case ItemStateNew, ItemStatePrepared:
+			if blockers := item.BlockedBy(outstanding); len(blockers) > 0 {
+				printers[i] = func() {
+					Log(item.Project, item.Type, item.Resource, ReasonWaitPending,
+						fmt.Sprintf("waiting for %s", strings.Join(blockers, ", ")))
+				}
+				continue
+			}
+			started = limiter.Go(item.Type, func() {
+				n.HandleRemove(item)
+			})
 		case ItemStateFailed:
-			n.HandleRemove(item)
-			n.HandleWait(item, listCache)
-			item.Print()
+			if item.Permanent {
+				started = limiter.Go(item.Type, func() {
+					n.HandleGone(item, cache)
+				})
+				break
+			}
+			if len(item.BlockedBy(outstanding)) > 0 {
+				break
+			}
+			// The budget is only taken once the retry actually starts, so
+			// items left for the next pass do not use it up.
+			started = limiter.Go(item.Type, func() {
+				if !n.retries.Take(item.Type) {
+					item.GiveUp(fmt.Sprintf("retry budget of %s exhausted", item.Type))
+					return
+				}
+				n.HandleRemove(item)
+				n.HandleWait(item, cache)
+			})
 		case ItemStatePending:
-			n.HandleWait(item, listCache)
-			item.State = ItemStateWaiting
-			item.Print()
+			started = limiter.Go(item.Type, func() {
+				n.HandleWait(item, cache)
+				if item.State == ItemStatePending {
+					item.State = ItemStateWaiting
+				}
+			})
 		case ItemStateWaiting:
-			n.HandleWait(item, listCache)
-			item.Print()
+			started = limiter.Go(item.Type, func() {
+				n.HandleWait(item, cache)
+			})
+		default:
+			continue
+		}
+
+		// Items of a saturated API are left as they are for the next pass.
+		if !started {
+			continue
 		}
 
+		printers[i] = item.Print
+	}
+
+	// Print the results in queue order, regardless of the order in which the
+	// requests finished.
+	limiter.Wait()
+	for _, printItem := range printers {
+		if printItem != nil {
+			printItem()
+		}
 	}
 
 	fmt.Println()
@@ -269,18 +722,49 @@ func (n *Nuke) HandleQueue() {
 }
 
 func (n *Nuke) HandleRemove(item *Item) {
-	clientGetter := resources.GetClient(item.Type)
-	gcpClient, err := clientGetter(item.Project)
+	// The run may have been cancelled while waiting for the limiter.
+	if item.Cancelled() {
+		return
+	}
+
+	var gcpClient gcputil.GCPClient
+	err := safeCall(func() (err error) {
+		clientGetter := getClient(item.Type)
+		gcpClient, err = clientGetter(item.Project)
+		return err
+	})
 	if err != nil {
 		dump := util.Indent(fmt.Sprintf("%v", err), "    ")
 		log.Errorf("Remove %s failed:\n%s", item.Type, dump)
+		item.Fail(err)
 		return
 	}
 
-	err = item.Resource.Remove(item.Project, gcpClient)
+	// Resources are prepared in a separate pass, so the output shows what got
+	// changed before the removal.
+	preparer, ok := item.Resource.(resources.Preparer)
+	if ok && item.State != ItemStatePrepared && n.featureFlags.DisableDeletionProtection.Enabled(item.Type) {
+		var prepared bool
+		err = safeCall(func() (err error) {
+			prepared, err = preparer.Prepare(item.Project, gcpClient)
+			return err
+		})
+		if err != nil {
+			item.Fail(err)
+			return
+		}
+		if prepared {
+			item.State = ItemStatePrepared
+			item.Reason = ""
+			return
+		}
+	}
+
+	err = safeCall(func() error {
+		return item.Resource.Remove(item.Project, gcpClient)
+	})
 	if err != nil {
-		item.State = ItemStateFailed
-		item.Reason = err.Error()
+		item.Fail(err)
 		return
 	}
 
@@ -288,41 +772,56 @@ func (n *Nuke) HandleRemove(item *Item) {
 	item.Reason = ""
 }
 
-func (n *Nuke) HandleWait(item *Item, cache map[string]map[string][]resources.Resource) {
-	var err error
-	project := item.Project.Name
-	if err := item.Resource.GetOperationError(item.Project.GetContext()); err != nil {
-		item.State = ItemStateFailed
-		item.Reason = err.Error()
-		return
+// HandleGone checks whether the resource of a permanently failed item still
+// exists. GCP removes some resources together with others, eg the routes of
+// a subnet, so they would block their dependents forever otherwise.
+func (n *Nuke) HandleGone(item *Item, cache *listCache) {
+	var (
+		exists bool
+		err    error
+	)
+	if _, ok := item.Resource.(resources.Getter); ok {
+		exists, err = item.Exists()
+	} else {
+		exists, err = item.Listed(cache)
 	}
-	_, ok := cache[project]
-	if !ok {
-		cache[project] = map[string][]resources.Resource{}
+	// The item failed already, so it stays failed, if the check fails, too.
+	if err != nil || exists {
+		return
 	}
-	left, ok := cache[project][item.Type]
-	if !ok {
-		left, err = item.List()
-		if err != nil {
-			item.State = ItemStateFailed
-			item.Reason = err.Error()
-			return
-		}
-		cache[project][item.Type] = left
+
+	item.State = ItemStateFinished
+	item.Reason = ""
+	item.Permanent = false
+}
+
+func (n *Nuke) HandleWait(item *Item, cache *listCache) {
+	if item.State != ItemStatePending && item.State != ItemStateWaiting {
+		return
 	}
 
-	for _, r := range left {
-		if item.Equals(r) {
-			checker, ok := r.(resources.Filter)
-			if ok {
-				err := checker.Filter()
-				if err != nil {
-					break
-				}
-			}
+	// An operation which is not found anymore says nothing about the
+	// resource, so it is checked by listing in that case.
+	err := safeCall(func() error {
+		return item.Resource.GetOperationError(item.Project.GetContext())
+	})
+	if err != nil && !gcputil.IsNotFound(err) {
+		item.Fail(err)
+		return
+	}
 
-			return
-		}
+	var exists bool
+	if _, ok := item.Resource.(resources.Getter); ok {
+		exists, err = item.Exists()
+	} else {
+		exists, err = item.Listed(cache)
+	}
+	if err != nil {
+		item.Delay(err)
+		return
+	}
+	if exists {
+		return
 	}
 
 	item.State = ItemStateFinished
@@ -2,11 +2,12 @@ package cmd
 
 import (
 	"fmt"
-	"strings"
+	"time"
 )
 
 type NukeParameters struct {
-	ConfigPath string
+	ConfigPaths   []string
+	ConfigSHA256s []string
 
 	Targets  []string
 	Excludes []string
@@ -15,14 +16,41 @@ type NukeParameters struct {
 	Force      bool
 	ForceSleep int
 	Quiet      bool
+	Explain    bool
 
-	MaxWaitRetries int
+	FailOnScanError bool
+	Verify          bool
+	VerifyRetries   int
+
+	MaxWaitRetries     int
+	MaxDuration        time.Duration
+	RemovalParallelism int
+
+	CheckpointPath string
+	ResumePath     string
 }
 
 func (p *NukeParameters) Validate() error {
-	if strings.TrimSpace(p.ConfigPath) == "" {
+	if len(p.ConfigPaths) == 0 {
 		return fmt.Errorf("You have to specify the --config flag.\n")
 	}
 
+	if p.RemovalParallelism < 1 {
+		return fmt.Errorf("The value for --removal-parallelism must be at least 1.\n")
+	}
+
+	if p.VerifyRetries < 0 {
+		return fmt.Errorf("The value for --verify-retries must not be negative.\n")
+	}
+	if p.VerifyRetries > 0 {
+		p.Verify = true
+	}
+
+	// A resumed run keeps its checkpoint up to date, unless another file is
+	// given explicitly.
+	if p.ResumePath != "" && p.CheckpointPath == "" {
+		p.CheckpointPath = p.ResumePath
+	}
+
 	return nil
 }
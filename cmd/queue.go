@@ -1,14 +1,24 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
+	"github.com/dshelley66/gcp-nuke/pkg/types"
 	"github.com/dshelley66/gcp-nuke/pkg/util"
 	"github.com/dshelley66/gcp-nuke/resources"
 	log "github.com/sirupsen/logrus"
 )
 
+// getClient and getLister look up the client getter and the lister of a
+// resource type. Tests replace them to provide fake clients and resources.
+var (
+	getClient = resources.GetClient
+	getLister = resources.GetLister
+)
+
 type ItemState int
 
 // States of Items based on the latest request to GCP.
@@ -19,6 +29,7 @@ const (
 	ItemStateFailed
 	ItemStateFiltered
 	ItemStateFinished
+	ItemStatePrepared
 )
 
 // An Item describes an actual GCP resource entity with the current state and
@@ -29,40 +40,203 @@ type Item struct {
 	State  ItemState
 	Reason string
 
+	// Explanation shows how a filter group decided to filter the item. With
+	// --explain, it is shown for every filtered item.
+	Explanation string
+
+	// Decision records which rule filtered the item.
+	Decision *FilterDecision
+
 	Project *gcputil.Project
 	Type    string
+
+	// Attempts counts the failed requests for the item. It is not retried
+	// before RetryAt and not at all, if the failure is Permanent.
+	Attempts  int
+	RetryAt   time.Time
+	Permanent bool
+}
+
+// Settings for the exponential backoff between retries of an item.
+const (
+	RetryBaseDelay = 5 * time.Second
+	RetryMaxDelay  = 5 * time.Minute
+	MaxAttempts    = 10
+)
+
+// Backoff returns the delay before the next retry after the given number of
+// failed attempts.
+func Backoff(attempts int) time.Duration {
+	delay := RetryBaseDelay
+	for i := 1; i < attempts && delay < RetryMaxDelay; i++ {
+		delay = delay * 2
+	}
+	if delay > RetryMaxDelay {
+		delay = RetryMaxDelay
+	}
+	return delay
+}
+
+// Fail records a failed request for the item. Depending on the class of the
+// error, the item is finished, retried after a backoff or given up.
+func (i *Item) Fail(err error) {
+	// A request which got cancelled together with the run says nothing about
+	// the resource, so it does not count as an attempt.
+	if i.Cancelled() {
+		return
+	}
+
+	// A panic is a bug in the resource implementation, which does not go
+	// away by retrying.
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		i.State = ItemStateFailed
+		i.Reason = err.Error()
+		i.GiveUp("")
+		return
+	}
+
+	class := gcputil.ClassifyError(err)
+	if class == gcputil.ErrorClassNotFound {
+		i.State = ItemStateFinished
+		i.Reason = ""
+		return
+	}
+
+	i.State = ItemStateFailed
+	i.Reason = err.Error()
+	i.delay(class)
+}
+
+// Delay records a failed request, which does not change the state of the
+// item, like a failed check whether a removal is done. Only permanent errors
+// fail the item.
+func (i *Item) Delay(err error) {
+	if i.Cancelled() {
+		return
+	}
+
+	var panicErr *PanicError
+	class := gcputil.ClassifyError(err)
+	if class == gcputil.ErrorClassPermanent || errors.As(err, &panicErr) {
+		i.Fail(err)
+		return
+	}
+
+	i.Reason = err.Error()
+	i.delay(class)
+}
+
+// Cancelled checks whether the context of the item's requests is done, eg
+// because the run got interrupted.
+func (i *Item) Cancelled() bool {
+	return i.Project != nil && i.Project.GetContext() != nil && i.Project.GetContext().Err() != nil
+}
+
+func (i *Item) delay(class gcputil.ErrorClass) {
+	i.Attempts = i.Attempts + 1
+	i.RetryAt = time.Now().Add(Backoff(i.Attempts))
+
+	switch {
+	case class == gcputil.ErrorClassPermanent:
+		i.GiveUp("")
+	case i.Attempts >= MaxAttempts:
+		i.GiveUp(fmt.Sprintf("giving up after %d attempts", i.Attempts))
+	}
+}
+
+// GiveUp fails the item permanently, so it is not retried anymore.
+func (i *Item) GiveUp(reason string) {
+	i.State = ItemStateFailed
+	i.Permanent = true
+	if reason != "" {
+		i.Reason = fmt.Sprintf("%s (%s)", i.Reason, reason)
+	}
 }
 
 func (i *Item) Print() {
 	switch i.State {
 	case ItemStateNew:
 		Log(i.Project, i.Type, i.Resource, ReasonWaitPending, "would remove")
+	case ItemStatePrepared:
+		Log(i.Project, i.Type, i.Resource, ReasonWaitPending, "prepared")
 	case ItemStatePending:
 		Log(i.Project, i.Type, i.Resource, ReasonWaitPending, "triggered remove")
 	case ItemStateWaiting:
 		Log(i.Project, i.Type, i.Resource, ReasonWaitPending, "waiting")
 	case ItemStateFailed:
-		Log(i.Project, i.Type, i.Resource, ReasonError, "failed")
+		if i.Permanent {
+			Log(i.Project, i.Type, i.Resource, ReasonError, "failed permanently")
+		} else {
+			Log(i.Project, i.Type, i.Resource, ReasonError, "failed")
+		}
 		ReasonError.Printf("ERROR: %v\n", i.Reason)
 	case ItemStateFiltered:
 		Log(i.Project, i.Type, i.Resource, ReasonSkip, i.Reason)
+		if i.Explanation != "" {
+			ReasonSkip.Printf("%s\n", util.Indent(i.Explanation, "    "))
+		}
 	case ItemStateFinished:
 		Log(i.Project, i.Type, i.Resource, ReasonSuccess, "removed")
 	}
 }
 
 // List gets all resource items of the same resource type like the Item.
-func (i *Item) List() ([]resources.Resource, error) {
-	clientGetter := resources.GetClient(i.Type)
-	gcpClient, err := clientGetter(i.Project)
+func (i *Item) List() (rs []resources.Resource, err error) {
+	err = safeCall(func() error {
+		clientGetter := getClient(i.Type)
+		gcpClient, err := clientGetter(i.Project)
+		if err != nil {
+			dump := util.Indent(fmt.Sprintf("%v", err), "    ")
+			log.Errorf("Listing %s failed:\n%s", i.Type, dump)
+			return err
+		}
+
+		lister := getLister(i.Type)
+		rs, err = lister(i.Project, gcpClient)
+		return err
+	})
+	return rs, err
+}
+
+// Exists checks with a single request whether the resource still exists. The
+// resource has to implement resources.Getter.
+func (i *Item) Exists() (bool, error) {
+	var exists bool
+	err := safeCall(func() error {
+		clientGetter := getClient(i.Type)
+		gcpClient, err := clientGetter(i.Project)
+		if err != nil {
+			return err
+		}
+
+		getter := i.Resource.(resources.Getter)
+		exists, err = getter.Exists(i.Project.GetContext(), i.Project, gcpClient)
+		return err
+	})
+	return exists, err
+}
+
+// Listed checks whether the resource is still listed. Resources which are
+// listed, but filtered by their own Filter, count as gone.
+func (i *Item) Listed(cache *listCache) (bool, error) {
+	left, err := cache.List(i)
 	if err != nil {
-		dump := util.Indent(fmt.Sprintf("%v", err), "    ")
-		log.Errorf("Listing %s failed:\n%s", i.Type, dump)
-		return nil, err
+		return false, err
 	}
 
-	lister := resources.GetLister(i.Type)
-	return lister(i.Project, gcpClient)
+	var listed bool
+	err = safeCall(func() error {
+		for _, r := range left {
+			if i.Equals(r) {
+				checker, ok := r.(resources.Filter)
+				listed = !ok || checker.Filter() == nil
+				return nil
+			}
+		}
+		return nil
+	})
+	return listed, err
 }
 
 func (i *Item) GetProperty(key string) (string, error) {
@@ -82,6 +256,16 @@ func (i *Item) GetProperty(key string) (string, error) {
 	return getter.Properties().Get(key), nil
 }
 
+// GetProperties returns all properties of the resource.
+func (i *Item) GetProperties() (types.Properties, error) {
+	getter, ok := i.Resource.(resources.ResourcePropertyGetter)
+	if !ok {
+		return nil, fmt.Errorf("%T does not support custom properties", i.Resource)
+	}
+
+	return getter.Properties(), nil
+}
+
 func (i *Item) Equals(o resources.Resource) bool {
 	iType := fmt.Sprintf("%T", i.Resource)
 	oType := fmt.Sprintf("%T", o)
@@ -110,6 +294,18 @@ func (i *Item) Equals(o resources.Resource) bool {
 	return false
 }
 
+// BlockedBy returns the resource types the item depends on, which still have
+// outstanding items in the queue.
+func (i *Item) BlockedBy(outstanding map[string]int) []string {
+	blockers := []string{}
+	for _, dependency := range resources.GetDependencies(i.Type) {
+		if outstanding[dependency] > 0 {
+			blockers = append(blockers, dependency)
+		}
+	}
+	return blockers
+}
+
 type Queue []*Item
 
 func (q Queue) CountTotal() int {
@@ -128,3 +324,68 @@ func (q Queue) Count(states ...ItemState) int {
 	}
 	return count
 }
+
+// Outstanding counts the items per resource type, which are neither removed
+// nor filtered and therefore still block removal of their dependents.
+func (q Queue) Outstanding() map[string]int {
+	outstanding := map[string]int{}
+	for _, item := range q {
+		switch item.State {
+		case ItemStateFiltered, ItemStateFinished:
+			continue
+		}
+		outstanding[item.Type] = outstanding[item.Type] + 1
+	}
+	return outstanding
+}
+
+// Find returns the item of the queue, which describes the same resource like
+// the given item.
+func (q Queue) Find(item *Item) *Item {
+	for _, candidate := range q {
+		if candidate.Type == item.Type && candidate.Equals(item.Resource) {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// CountReady counts the new and prepared items whose dependencies are all
+// removed, so their removal can be triggered.
+func (q Queue) CountReady() int {
+	outstanding := q.Outstanding()
+	count := 0
+	for _, item := range q {
+		if (item.State == ItemStateNew || item.State == ItemStatePrepared) && len(item.BlockedBy(outstanding)) == 0 {
+			count = count + 1
+		}
+	}
+	return count
+}
+
+// CountRetryable counts the failed items, which are going to be retried.
+func (q Queue) CountRetryable() int {
+	outstanding := q.Outstanding()
+	count := 0
+	for _, item := range q {
+		if item.State == ItemStateFailed && !item.Permanent && len(item.BlockedBy(outstanding)) == 0 {
+			count = count + 1
+		}
+	}
+	return count
+}
+
+// NextRetry returns the earliest time at which a failed item is retried. It
+// returns the zero time, if there is no such item.
+func (q Queue) NextRetry() time.Time {
+	next := time.Time{}
+	for _, item := range q {
+		if item.State != ItemStateFailed || item.Permanent {
+			continue
+		}
+		if next.IsZero() || item.RetryAt.Before(next) {
+			next = item.RetryAt
+		}
+	}
+	return next
+}
@@ -0,0 +1,418 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dshelley66/gcp-nuke/pkg/config"
+	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
+	"github.com/dshelley66/gcp-nuke/resources"
+	"google.golang.org/api/googleapi"
+)
+
+type testResource struct {
+	name string
+}
+
+func (r *testResource) Remove(*gcputil.Project, gcputil.GCPClient) error {
+	return nil
+}
+
+func (r *testResource) GetOperationError(context.Context) error {
+	return nil
+}
+
+func (r *testResource) String() string {
+	return r.name
+}
+
+func newTestItem(resourceType string, state ItemState) *Item {
+	return &Item{
+		Resource: &testResource{name: fmt.Sprintf("%s-%d", resourceType, state)},
+		State:    state,
+		Type:     resourceType,
+	}
+}
+
+func TestQueueDependencies(t *testing.T) {
+	cases := []struct {
+		name    string
+		queue   Queue
+		blocked []string
+		ready   int
+	}{
+		{
+			name: "NoDependencies",
+			queue: Queue{
+				newTestItem(resources.ResourceTypeVPC, ItemStateNew),
+			},
+			blocked: []string{},
+			ready:   1,
+		},
+		{
+			name: "BlockedByNewAndFailed",
+			queue: Queue{
+				newTestItem(resources.ResourceTypeVPC, ItemStateNew),
+				newTestItem(resources.ResourceTypeSubnet, ItemStateFailed),
+				newTestItem(resources.ResourceTypeFirewall, ItemStateNew),
+				newTestItem(resources.ResourceTypeRoute, ItemStateFinished),
+			},
+			blocked: []string{resources.ResourceTypeSubnet, resources.ResourceTypeFirewall},
+			ready:   1,
+		},
+		{
+			name: "FilteredAndFinishedDoNotBlock",
+			queue: Queue{
+				newTestItem(resources.ResourceTypeVPC, ItemStateNew),
+				newTestItem(resources.ResourceTypeSubnet, ItemStateFiltered),
+				newTestItem(resources.ResourceTypeFirewall, ItemStateFinished),
+			},
+			blocked: []string{},
+			ready:   1,
+		},
+		{
+			name: "PreparedIsReady",
+			queue: Queue{
+				newTestItem(resources.ResourceTypeVPC, ItemStatePrepared),
+				newTestItem(resources.ResourceTypeSubnet, ItemStateFinished),
+			},
+			blocked: []string{},
+			ready:   1,
+		},
+		{
+			name: "BlockedByPrepared",
+			queue: Queue{
+				newTestItem(resources.ResourceTypeVPC, ItemStateNew),
+				newTestItem(resources.ResourceTypeSubnet, ItemStatePrepared),
+			},
+			blocked: []string{resources.ResourceTypeSubnet},
+			ready:   1,
+		},
+		{
+			name: "BlockedByWaiting",
+			queue: Queue{
+				newTestItem(resources.ResourceTypeVPC, ItemStateNew),
+				newTestItem(resources.ResourceTypeRouter, ItemStateWaiting),
+			},
+			blocked: []string{resources.ResourceTypeRouter},
+			ready:   0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			blocked := tc.queue[0].BlockedBy(tc.queue.Outstanding())
+
+			var (
+				want = fmt.Sprint(tc.blocked)
+				have = fmt.Sprint(blocked)
+			)
+			if want != have {
+				t.Errorf("Wrong blockers. Want: %s. Have: %s", want, have)
+			}
+
+			if ready := tc.queue.CountReady(); ready != tc.ready {
+				t.Errorf("Wrong ready count. Want: %d. Have: %d", tc.ready, ready)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 5 * time.Second},
+		{attempts: 2, want: 10 * time.Second},
+		{attempts: 4, want: 40 * time.Second},
+		{attempts: 7, want: 5 * time.Minute},
+		{attempts: 100, want: 5 * time.Minute},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprint(tc.attempts), func(t *testing.T) {
+			if have := Backoff(tc.attempts); have != tc.want {
+				t.Errorf("Wrong backoff. Want: %v. Have: %v", tc.want, have)
+			}
+		})
+	}
+}
+
+func TestItemFail(t *testing.T) {
+	cases := []struct {
+		name      string
+		err       error
+		state     ItemState
+		permanent bool
+	}{
+		{
+			name:  "NotFound",
+			err:   &googleapi.Error{Code: 404},
+			state: ItemStateFinished,
+		},
+		{
+			name:  "Retryable",
+			err:   &googleapi.Error{Code: 429},
+			state: ItemStateFailed,
+		},
+		{
+			name:      "Permanent",
+			err:       &googleapi.Error{Code: 403},
+			state:     ItemStateFailed,
+			permanent: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			item := newTestItem(resources.ResourceTypeVPC, ItemStatePending)
+			item.Fail(tc.err)
+
+			if item.State != tc.state {
+				t.Errorf("Wrong state. Want: %s. Have: %s", tc.state, item.State)
+			}
+			if item.Permanent != tc.permanent {
+				t.Errorf("Wrong permanent flag. Want: %t. Have: %t", tc.permanent, item.Permanent)
+			}
+		})
+	}
+
+	item := newTestItem(resources.ResourceTypeVPC, ItemStateFailed)
+	for i := 0; i < MaxAttempts; i++ {
+		item.Fail(&googleapi.Error{Code: 503})
+	}
+	if !item.Permanent {
+		t.Errorf("Item was not given up after %d attempts.", MaxAttempts)
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	budget := newRetryBudget(map[string]int{resources.ResourceTypeVPC: 2, resources.ResourceTypeRoute: 0})
+
+	for i, want := range []bool{true, true, false} {
+		if have := budget.Take(resources.ResourceTypeVPC); have != want {
+			t.Errorf("Wrong result of take %d. Want: %t. Have: %t", i, want, have)
+		}
+	}
+
+	if !budget.Take(resources.ResourceTypeSubnet) {
+		t.Errorf("Type without budget got limited.")
+	}
+	if budget.Take(resources.ResourceTypeRoute) {
+		t.Errorf("Type with a budget of 0 got retried.")
+	}
+}
+
+func TestQueueFind(t *testing.T) {
+	queue := Queue{
+		newTestItem(resources.ResourceTypeVPC, ItemStateFinished),
+		newTestItem(resources.ResourceTypeSubnet, ItemStateFailed),
+	}
+
+	rescanned := &Item{
+		Resource: &testResource{name: queue[1].Resource.(*testResource).name},
+		Type:     resources.ResourceTypeSubnet,
+	}
+	if found := queue.Find(rescanned); found != queue[1] {
+		t.Errorf("Wrong item found. Want: %v. Have: %v", queue[1], found)
+	}
+
+	appeared := &Item{
+		Resource: &testResource{name: "created-during-run"},
+		Type:     resources.ResourceTypeSubnet,
+	}
+	if found := queue.Find(appeared); found != nil {
+		t.Errorf("Found an item for a new resource: %v", found)
+	}
+}
+
+func TestHandleRemoveClientError(t *testing.T) {
+	getClient = func(string) resources.ResourceClientGetter {
+		return func(*gcputil.Project) (gcputil.GCPClient, error) {
+			return nil, fmt.Errorf("could not create client")
+		}
+	}
+	t.Cleanup(func() { getClient = resources.GetClient })
+
+	item := newTestItem(resources.ResourceTypeVPC, ItemStateNew)
+	new(Nuke).HandleRemove(item)
+
+	if item.State != ItemStateFailed {
+		t.Fatalf("Wrong state. Want: %v. Have: %v", ItemStateFailed, item.State)
+	}
+	if item.Attempts != 1 || item.RetryAt.IsZero() {
+		t.Errorf("The failure got no backoff. Attempts: %d. Retry at: %v", item.Attempts, item.RetryAt)
+	}
+}
+
+func TestCheckDependencies(t *testing.T) {
+	if err := resources.CheckDependencies(); err != nil {
+		t.Fatalf("The registered dependencies are invalid: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		methods resources.ResourceMethods
+		want    string
+	}{
+		{
+			name: "Valid",
+			methods: resources.ResourceMethods{
+				"VPC":    {DependsOn: []string{"Subnet", "Route"}},
+				"Subnet": {DependsOn: []string{"Route"}},
+				"Route":  {},
+			},
+		},
+		{
+			name: "Unknown",
+			methods: resources.ResourceMethods{
+				"VPC":    {DependsOn: []string{"Subnett"}},
+				"Subnet": {},
+			},
+			want: "the resource type VPC depends on the unknown resource type Subnett",
+		},
+		{
+			name: "Cycle",
+			methods: resources.ResourceMethods{
+				"Network": {DependsOn: []string{"VPC"}},
+				"Route":   {},
+				"Subnet":  {DependsOn: []string{"Route", "VPC"}},
+				"VPC":     {DependsOn: []string{"Subnet"}},
+			},
+			want: "the resource types have a dependency cycle: VPC -> Subnet -> VPC",
+		},
+		{
+			name: "Self",
+			methods: resources.ResourceMethods{
+				"VPC": {DependsOn: []string{"VPC"}},
+			},
+			want: "the resource types have a dependency cycle: VPC -> VPC",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.methods.CheckDependencies()
+			if tc.want == "" {
+				if err != nil {
+					t.Fatalf("Didn't expect an error, but got one: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.want {
+				t.Fatalf("Wrong error. Want: %s. Have: %v", tc.want, err)
+			}
+		})
+	}
+}
+
+// goneResource is a resource, which can check its existence.
+type goneResource struct {
+	testResource
+	exists bool
+}
+
+func (r *goneResource) Exists(context.Context, *gcputil.Project, gcputil.GCPClient) (bool, error) {
+	return r.exists, nil
+}
+
+func TestPermanentlyFailedGone(t *testing.T) {
+	getClient = func(string) resources.ResourceClientGetter {
+		return func(*gcputil.Project) (gcputil.GCPClient, error) {
+			return nil, nil
+		}
+	}
+	t.Cleanup(func() { getClient = resources.GetClient })
+
+	// The route got removed together with its subnet, after its own removal
+	// failed.
+	gone := &Item{
+		Resource:  &goneResource{testResource: testResource{name: "subnet-route"}},
+		State:     ItemStateFailed,
+		Type:      resources.ResourceTypeRoute,
+		Permanent: true,
+		RetryAt:   time.Now().Add(time.Hour),
+	}
+	remaining := &Item{
+		Resource:  &goneResource{testResource: testResource{name: "allow-ssh"}, exists: true},
+		State:     ItemStateFailed,
+		Type:      resources.ResourceTypeFirewall,
+		Permanent: true,
+	}
+	vpc := newTestItem(resources.ResourceTypeVPC, ItemStateNew)
+
+	project := gcputil.NewProject(context.Background(), &gcputil.Credentials{Project: "test"})
+	for _, item := range []*Item{gone, remaining, vpc} {
+		item.Project = project
+	}
+
+	n := &Nuke{
+		Config:     &config.Nuke{},
+		Parameters: NukeParameters{RemovalParallelism: 2},
+		retries:    newRetryBudget(nil),
+		items:      Queue{vpc, gone, remaining},
+	}
+	n.HandleQueue()
+
+	if gone.State != ItemStateFinished || gone.Permanent {
+		t.Errorf("Wrong state of the removed item. Want: %v. Have: %v (permanent: %t)", ItemStateFinished, gone.State, gone.Permanent)
+	}
+	if remaining.State != ItemStateFailed || !remaining.Permanent {
+		t.Errorf("Wrong state of the remaining item. Want: permanently failed. Have: %v (permanent: %t)", remaining.State, remaining.Permanent)
+	}
+
+	blockers := vpc.BlockedBy(n.items.Outstanding())
+	if want := []string{resources.ResourceTypeFirewall}; fmt.Sprint(blockers) != fmt.Sprint(want) {
+		t.Errorf("Wrong blockers. Want: %v. Have: %v", want, blockers)
+	}
+}
+
+func TestCancelledQueue(t *testing.T) {
+	requests := 0
+	previous := getClient
+	getClient = func(string) resources.ResourceClientGetter {
+		requests = requests + 1
+		return func(*gcputil.Project) (gcputil.GCPClient, error) {
+			return nil, fmt.Errorf("no client")
+		}
+	}
+	t.Cleanup(func() { getClient = previous })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	project := gcputil.NewProject(ctx, &gcputil.Credentials{Project: "test"})
+
+	items := Queue{
+		newTestItem(resources.ResourceTypeVPC, ItemStateNew),
+		newTestItem(resources.ResourceTypeFirewall, ItemStateFailed),
+	}
+	for _, item := range items {
+		item.Project = project
+	}
+
+	n := &Nuke{
+		Config:     &config.Nuke{},
+		Parameters: NukeParameters{RemovalParallelism: 2},
+		retries:    newRetryBudget(nil),
+		items:      items,
+	}
+	n.HandleQueue()
+
+	if requests != 0 {
+		t.Errorf("Removals were started after the cancellation. Want: 0. Have: %d", requests)
+	}
+
+	// A request failing due to the cancellation is no attempt.
+	items[1].Fail(context.Canceled)
+	for _, item := range items {
+		if item.Attempts != 0 || item.Permanent {
+			t.Errorf("Wrong retry state of %s. Want: 0, false. Have: %d, %t", item.Type, item.Attempts, item.Permanent)
+		}
+	}
+	if items[0].State != ItemStateNew {
+		t.Errorf("Wrong state. Want: %s. Have: %s", ItemStateNew, items[0].State)
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import "sync"
+
+// retryBudget limits the total number of retries per resource type, so a
+// type which keeps failing does not hammer its API.
+type retryBudget struct {
+	mu     sync.Mutex
+	limits map[string]int
+	used   map[string]int
+}
+
+func newRetryBudget(limits map[string]int) *retryBudget {
+	return &retryBudget{
+		limits: limits,
+		used:   map[string]int{},
+	}
+}
+
+// Take consumes one retry of the resource type. It returns false, if the
+// budget is exhausted. Types without a configured budget are not limited, while
+// a budget of 0 allows no retries at all. It is safe for concurrent use.
+func (b *retryBudget) Take(resourceType string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	limit, ok := b.limits[resourceType]
+	if !ok {
+		return true
+	}
+
+	if b.used[resourceType] >= limit {
+		return false
+	}
+
+	b.used[resourceType] = b.used[resourceType] + 1
+	return true
+}
@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
 	"sort"
+	"strings"
+	"syscall"
 
 	"github.com/dshelley66/gcp-nuke/pkg/config"
 	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
@@ -49,16 +54,37 @@ func NewRootCommand() *cobra.Command {
 
 		command.SilenceUsage = true
 
-		config, err := config.Load(params.ConfigPath)
+		loader, err := NewConfigLoader(params.ConfigPaths, params.ConfigSHA256s, &creds)
 		if err != nil {
-			log.Errorf("Failed to parse config file %s", params.ConfigPath)
 			return err
 		}
+		loader.Variables[config.VariableProjectID] = creds.Project
+
+		config, err := loader.Load(params.ConfigPaths...)
+		if err != nil {
+			log.Errorf("Failed to load config file %s, refusing to run.", strings.Join(params.ConfigPaths, ", "))
+			return err
+		}
+
+		// The first SIGINT or SIGTERM cancels the run gracefully. Afterwards the
+		// default behaviour is restored, so a second one kills the process.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			stop()
+		}()
+
+		if params.MaxDuration > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, params.MaxDuration)
+			defer cancel()
+		}
 
 		n := NewNuke(params, &creds)
 
 		n.Config = config
-		n.Project = gcputil.NewProject(&creds)
+		n.Project = gcputil.NewProject(ctx, &creds)
 
 		return n.Run()
 	}
@@ -67,9 +93,15 @@ func NewRootCommand() *cobra.Command {
 		&verbose, "verbose", "v", false,
 		"Enables debug output.")
 
-	command.PersistentFlags().StringVarP(
-		&params.ConfigPath, "config", "c", "",
-		"(required) Path to the nuke config file.")
+	command.PersistentFlags().StringArrayVarP(
+		&params.ConfigPaths, "config", "c", []string{},
+		"(required) Path to the nuke config file. "+
+			"This flag can be used multiple times, later files are layered over earlier ones. "+
+			"Remote files can be read from Cloud Storage (gs://bucket/object) or https URLs.")
+	command.PersistentFlags().StringArrayVar(
+		&params.ConfigSHA256s, "config-sha256", []string{},
+		"Expected SHA-256 of the config file as hex string. The run is refused, if the content differs. "+
+			"If used, it has to be specified once for every --config flag, in the same order.")
 
 	command.PersistentFlags().StringVarP(
 		&creds.Keyfile, "keyfile", "k", "",
@@ -103,12 +135,45 @@ func NewRootCommand() *cobra.Command {
 		&params.MaxWaitRetries, "max-wait-retries", 0,
 		"If specified, the program will exit if resources are stuck in waiting for this many iterations. "+
 			"0 (default) disables early exit.")
+	command.PersistentFlags().IntVar(
+		&params.RemovalParallelism, "removal-parallelism", 1,
+		"Maximum number of removal and wait requests which are sent to GCP in parallel. "+
+			"Limits per API can be configured with removal-parallelism in the config file.")
+	command.PersistentFlags().DurationVar(
+		&params.MaxDuration, "max-duration", 0,
+		"If specified, the run gets cancelled after this duration (eg 2h). Triggered removals are still "+
+			"awaited for a short grace period. 0 (default) disables the limit.")
+	command.PersistentFlags().BoolVar(
+		&params.FailOnScanError, "fail-on-scan-error", false,
+		"If specified, the run fails when listing any resource type failed. Types which are skipped, "+
+			"because their API is disabled, do not count as failures.")
+	command.PersistentFlags().BoolVar(
+		&params.Verify, "verify", false,
+		"If specified, the project is scanned again after the run and the run fails, "+
+			"if any nukeable resources remain.")
+	command.PersistentFlags().IntVar(
+		&params.VerifyRetries, "verify-retries", 0,
+		"If specified together with --verify, remaining resources are nuked again up to this many times "+
+			"before the run fails.")
+	command.PersistentFlags().StringVar(
+		&params.CheckpointPath, "checkpoint", "",
+		"If specified, the queue is written to this file after the scan and after every removal pass, "+
+			"so an aborted run can be continued with --resume. It also records which filter matched each resource.")
+	command.PersistentFlags().StringVar(
+		&params.ResumePath, "resume", "",
+		"Continue the run from this checkpoint file. Triggered removals are awaited again instead of "+
+			"being triggered a second time. The checkpoint file gets updated, unless --checkpoint is set.")
 	command.PersistentFlags().BoolVarP(
 		&params.Quiet, "quiet", "q", false,
 		"Don't show filtered resources.")
+	command.PersistentFlags().BoolVar(
+		&params.Explain, "explain", false,
+		"Show which filter of the config, including its preset, filtered each resource, "+
+			"and print how many resources every filter matched.")
 
 	command.AddCommand(NewVersionCommand())
 	command.AddCommand(NewResourceTypesCommand())
+	command.AddCommand(NewConfigCommand(&params, &creds))
 
 	return command
 }
@@ -122,7 +187,13 @@ func NewResourceTypesCommand() *cobra.Command {
 			sort.Strings(names)
 
 			for _, resourceType := range names {
-				fmt.Println(resourceType)
+				aliases := resources.GetAliases(resourceType)
+				if len(aliases) == 0 {
+					fmt.Println(resourceType)
+					continue
+				}
+
+				fmt.Printf("%s (deprecated aliases: %s)\n", resourceType, strings.Join(aliases, ", "))
 			}
 		},
 	}
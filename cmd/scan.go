@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
 	"github.com/dshelley66/gcp-nuke/pkg/util"
@@ -14,19 +17,31 @@ import (
 
 const ScannerParallelQueries = 16
 
-func Scan(project *gcputil.Project, resourceTypes []string) <-chan *Item {
+// ScannerMaxAttempts is the number of attempts for listing a resource type,
+// if the requests fail with retryable errors.
+const ScannerMaxAttempts = 3
+
+// scanBackoff returns the delay before the next attempt to list a resource
+// type. Tests replace it to avoid waiting.
+var scanBackoff = Backoff
+
+// Scan lists all given resource types. The outcome of each type is recorded
+// in the returned report, which is complete once the channel is closed.
+func Scan(project *gcputil.Project, resourceTypes []string) (<-chan *Item, *ScanReport) {
 	s := &scanner{
 		items:     make(chan *Item, 100),
 		semaphore: semaphore.NewWeighted(ScannerParallelQueries),
+		report:    new(ScanReport),
 	}
 	go s.run(project, resourceTypes)
 
-	return s.items
+	return s.items, s.report
 }
 
 type scanner struct {
 	items     chan *Item
 	semaphore *semaphore.Weighted
+	report    *ScanReport
 }
 
 func (s *scanner) run(project *gcputil.Project, resourceTypes []string) {
@@ -44,43 +59,67 @@ func (s *scanner) run(project *gcputil.Project, resourceTypes []string) {
 }
 
 func (s *scanner) list(project *gcputil.Project, resourceType string) {
+	defer s.semaphore.Release(1)
 	defer func() {
 		if r := recover(); r != nil {
-			err := fmt.Errorf("%v\n\n%s", r.(error), string(debug.Stack()))
+			err := &PanicError{Value: r, Stack: debug.Stack()}
 			dump := util.Indent(fmt.Sprintf("%v", err), "    ")
 			log.Errorf("Listing %s failed:\n%s", resourceType, dump)
+			s.report.add(resourceType, ScanFailed, 0, err)
 		}
 	}()
-	defer s.semaphore.Release(1)
 
-	clientGetter := resources.GetClient(resourceType)
+	clientGetter := getClient(resourceType)
 	gcpClient, err := clientGetter(project)
 	if err != nil {
 		dump := util.Indent(fmt.Sprintf("%v", err), "    ")
 		log.Errorf("Listing %s failed:\n%s", resourceType, dump)
+		s.report.add(resourceType, ScanFailed, 0, err)
 		return
 	}
-	lister := resources.GetLister(resourceType)
+	lister := getLister(resourceType)
 	var rs []resources.Resource
-	rs, err = lister(project, gcpClient)
+	for attempt := 1; ; attempt++ {
+		rs, err = lister(project, gcpClient)
+		if err == nil || attempt >= ScannerMaxAttempts || gcputil.ClassifyError(err) != gcputil.ErrorClassRetryable {
+			break
+		}
+
+		log.Debugf("Listing %s failed, retrying: %v", resourceType, err)
+		if Sleep(project.GetContext(), scanBackoff(attempt)) != nil {
+			break
+		}
+	}
 	if err != nil {
 		_, ok := err.(gcputil.ErrSkipRequest)
 		if ok {
 			log.Debugf("skipping request: %v", err)
+			s.report.add(resourceType, ScanSkipped, 0, err)
 			return
 		}
 
 		_, ok = err.(gcputil.ErrUnknownEndpoint)
 		if ok {
 			log.Warnf("skipping request: %v", err)
+			s.report.add(resourceType, ScanSkipped, 0, err)
+			return
+		}
+
+		if gcputil.IsServiceDisabled(err) {
+			log.Debugf("skipping %s, because its API is disabled: %v", resourceType, err)
+			s.report.add(resourceType, ScanSkipped, 0,
+				fmt.Errorf("API %s is disabled", resources.GetAPI(resourceType)))
 			return
 		}
 
 		dump := util.Indent(fmt.Sprintf("%v", err), "    ")
 		log.Errorf("Listing %s failed:\n%s", resourceType, dump)
+		s.report.add(resourceType, ScanFailed, 0, err)
 		return
 	}
 
+	s.report.add(resourceType, ScanOK, len(rs), nil)
+
 	for _, r := range rs {
 		s.items <- &Item{
 			Project:  project,
@@ -90,3 +129,102 @@ func (s *scanner) list(project *gcputil.Project, resourceType string) {
 		}
 	}
 }
+
+// ScanOutcome describes the result of listing a single resource type.
+type ScanOutcome int
+
+const (
+	ScanOK ScanOutcome = iota
+	ScanSkipped
+	ScanFailed
+)
+
+func (o ScanOutcome) String() string {
+	switch o {
+	case ScanOK:
+		return "ok"
+	case ScanSkipped:
+		return "skipped"
+	case ScanFailed:
+		return "failed"
+	}
+	return fmt.Sprintf("ScanOutcome(%d)", int(o))
+}
+
+type ScanResult struct {
+	Type    string
+	Outcome ScanOutcome
+	Count   int
+	Err     error
+}
+
+// ScanReport collects the outcome of every listed resource type.
+type ScanReport struct {
+	mu      sync.Mutex
+	Results []ScanResult
+}
+
+func (r *ScanReport) add(resourceType string, outcome ScanOutcome, count int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Results = append(r.Results, ScanResult{
+		Type:    resourceType,
+		Outcome: outcome,
+		Count:   count,
+		Err:     err,
+	})
+}
+
+func (r *ScanReport) Count(outcome ScanOutcome) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, result := range r.Results {
+		if result.Outcome == outcome {
+			count = count + 1
+		}
+	}
+	return count
+}
+
+// Print prints the resource types which were not listed successfully, sorted
+// by name.
+func (r *ScanReport) Print() {
+	r.mu.Lock()
+	results := make([]ScanResult, len(r.Results))
+	copy(results, r.Results)
+	r.mu.Unlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Type < results[j].Type
+	})
+
+	fmt.Printf("Scan report: %d ok, %d skipped, %d failed.\n",
+		r.Count(ScanOK), r.Count(ScanSkipped), r.Count(ScanFailed))
+
+	for _, result := range results {
+		switch result.Outcome {
+		case ScanOK:
+			log.Debugf("%s - ok - %d resources", result.Type, result.Count)
+		case ScanSkipped:
+			ColorResourceType.Printf("    %s", result.Type)
+			fmt.Printf(" - ")
+			ReasonSkip.Printf("skipped: %v\n", result.Err)
+		case ScanFailed:
+			ColorResourceType.Printf("    %s", result.Type)
+			fmt.Printf(" - ")
+			ReasonError.Printf("failed: %s\n", firstLine(result.Err.Error()))
+		}
+	}
+
+	fmt.Println()
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
+	"github.com/dshelley66/gcp-nuke/resources"
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/api/googleapi"
+)
+
+func TestScanReport(t *testing.T) {
+	report := new(ScanReport)
+	report.add(resources.ResourceTypeVPC, ScanOK, 3, nil)
+	report.add(resources.ResourceTypeSubnet, ScanOK, 0, nil)
+	report.add(resources.ResourceTypeRedis, ScanSkipped, 0, errors.New("API redis is disabled"))
+	report.add(resources.ResourceTypeCloudSQL, ScanFailed, 0, errors.New("permission denied\n\nstack"))
+
+	cases := []struct {
+		outcome ScanOutcome
+		want    int
+	}{
+		{outcome: ScanOK, want: 2},
+		{outcome: ScanSkipped, want: 1},
+		{outcome: ScanFailed, want: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.outcome.String(), func(t *testing.T) {
+			if have := report.Count(tc.outcome); have != tc.want {
+				t.Errorf("Wrong count. Want: %d. Have: %d", tc.want, have)
+			}
+		})
+	}
+}
+
+// listWithErrors lists a single test resource after returning the given
+// errors, one per attempt.
+func listWithErrors(t *testing.T, errs ...error) (*ScanReport, int) {
+	attempts := 0
+	getClient = func(string) resources.ResourceClientGetter {
+		return func(*gcputil.Project) (gcputil.GCPClient, error) {
+			return nil, nil
+		}
+	}
+	getLister = func(string) resources.ResourceLister {
+		return func(*gcputil.Project, gcputil.GCPClient) ([]resources.Resource, error) {
+			attempts = attempts + 1
+			if attempts <= len(errs) {
+				return nil, errs[attempts-1]
+			}
+			return []resources.Resource{&testResource{name: "scanned"}}, nil
+		}
+	}
+	scanBackoff = func(int) time.Duration { return 0 }
+	t.Cleanup(func() {
+		getClient = resources.GetClient
+		getLister = resources.GetLister
+		scanBackoff = Backoff
+	})
+
+	s := &scanner{
+		items:     make(chan *Item, 1),
+		semaphore: semaphore.NewWeighted(1),
+		report:    new(ScanReport),
+	}
+	s.semaphore.Acquire(context.Background(), 1)
+
+	project := gcputil.NewProject(context.Background(), &gcputil.Credentials{Project: "test"})
+	s.list(project, resources.ResourceTypeRedis)
+
+	return s.report, attempts
+}
+
+func TestScannerList(t *testing.T) {
+	disabled := &googleapi.Error{
+		Code:   http.StatusForbidden,
+		Errors: []googleapi.ErrorItem{{Reason: "accessNotConfigured"}},
+	}
+	unavailable := &googleapi.Error{Code: http.StatusServiceUnavailable}
+
+	cases := []struct {
+		name     string
+		errs     []error
+		outcome  ScanOutcome
+		count    int
+		attempts int
+	}{
+		{
+			name:     "OK",
+			outcome:  ScanOK,
+			count:    1,
+			attempts: 1,
+		},
+		{
+			name:     "APIDisabled",
+			errs:     []error{disabled},
+			outcome:  ScanSkipped,
+			attempts: 1,
+		},
+		{
+			name:     "Retried",
+			errs:     []error{unavailable, unavailable},
+			outcome:  ScanOK,
+			count:    1,
+			attempts: 3,
+		},
+		{
+			name:     "RetriesExhausted",
+			errs:     []error{unavailable, unavailable, unavailable},
+			outcome:  ScanFailed,
+			attempts: ScannerMaxAttempts,
+		},
+		{
+			name:     "Permanent",
+			errs:     []error{&googleapi.Error{Code: http.StatusForbidden}},
+			outcome:  ScanFailed,
+			attempts: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, attempts := listWithErrors(t, tc.errs...)
+
+			if len(report.Results) != 1 {
+				t.Fatalf("Wrong number of results. Want: 1. Have: %d", len(report.Results))
+			}
+			result := report.Results[0]
+			if result.Outcome != tc.outcome {
+				t.Errorf("Wrong outcome. Want: %v. Have: %v (%v)", tc.outcome, result.Outcome, result.Err)
+			}
+			if result.Count != tc.count {
+				t.Errorf("Wrong count. Want: %d. Have: %d", tc.count, result.Count)
+			}
+			if attempts != tc.attempts {
+				t.Errorf("Wrong number of attempts. Want: %d. Have: %d", tc.attempts, attempts)
+			}
+		})
+	}
+}
+
+func TestFailOnScanError(t *testing.T) {
+	disabled := &googleapi.Error{
+		Code:   http.StatusForbidden,
+		Errors: []googleapi.ErrorItem{{Reason: "accessNotConfigured"}},
+	}
+
+	n := &Nuke{Parameters: NukeParameters{FailOnScanError: true}}
+	n.scanReport, _ = listWithErrors(t, disabled)
+	if err := n.scanError(); err != nil {
+		t.Errorf("A type of a disabled API failed the run: %v", err)
+	}
+
+	n.scanReport.add(resources.ResourceTypeVPC, ScanFailed, 0, errors.New("permission denied"))
+	if err := n.scanError(); err == nil {
+		t.Errorf("Expected an error for a failed type but didn't get one.")
+	}
+
+	n.Parameters.FailOnScanError = false
+	if err := n.scanError(); err != nil {
+		t.Errorf("Didn't expect an error without --fail-on-scan-error, but got one: %v", err)
+	}
+}
@@ -2,22 +2,44 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/dshelley66/gcp-nuke/pkg/config"
 	"github.com/dshelley66/gcp-nuke/pkg/types"
+	log "github.com/sirupsen/logrus"
 )
 
-func Prompt(expect string) error {
+func Prompt(ctx context.Context, expect string) error {
+	type answer struct {
+		text string
+		err  error
+	}
+
 	fmt.Print("> ")
-	reader := bufio.NewReader(os.Stdin)
-	text, err := reader.ReadString('\n')
-	if err != nil {
-		return err
+	answers := make(chan answer, 1)
+	go func() {
+		reader := bufio.NewReader(os.Stdin)
+		text, err := reader.ReadString('\n')
+		answers <- answer{text: text, err: err}
+	}()
+
+	var a answer
+	select {
+	case <-ctx.Done():
+		fmt.Println()
+		return contextError(ctx)
+	case a = <-answers:
 	}
 
-	if strings.TrimSpace(text) != expect {
+	if a.err != nil {
+		return a.err
+	}
+
+	if strings.TrimSpace(a.text) != expect {
 		return fmt.Errorf("aborted")
 	}
 	fmt.Println()
@@ -25,6 +47,20 @@ func Prompt(expect string) error {
 	return nil
 }
 
+// Sleep waits for the given duration or until the context is done. In the
+// latter case it returns the reason why the context is done.
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return contextError(ctx)
+	case <-timer.C:
+		return nil
+	}
+}
+
 func ResolveResourceTypes(
 	base types.Collection, mapping map[string]string,
 	include, exclude, cloudControl []types.Collection) types.Collection {
@@ -55,6 +91,55 @@ func ResolveResourceTypes(
 	return base
 }
 
+// ResolveAliases replaces former names of resource types by their current
+// names and warns about each of them.
+func ResolveAliases(collection types.Collection, aliases map[string]string) types.Collection {
+	if len(collection) == 0 {
+		return collection
+	}
+
+	resolved := types.Collection{}
+	for _, resourceType := range collection {
+		resolved = append(resolved, resolveAlias(resourceType, aliases))
+	}
+	return resolved
+}
+
+// ResolveFilterAliases moves the filters of former resource type names to
+// their current names.
+func ResolveFilterAliases(filters config.Filters, aliases map[string]string) config.Filters {
+	resolved := config.Filters{}
+	for resourceType, list := range filters {
+		resourceType = resolveAlias(resourceType, aliases)
+		resolved[resourceType] = append(resolved[resourceType], list...)
+	}
+	return resolved
+}
+
+// ResolveFlagAliases moves the flags of former resource type names to their
+// current names. Flags of the current names take precedence.
+func ResolveFlagAliases(flags config.DisableDeletionProtection, aliases map[string]string) config.DisableDeletionProtection {
+	resolved := config.DisableDeletionProtection{}
+	for resourceType, enabled := range flags {
+		current := resolveAlias(resourceType, aliases)
+		if _, ok := flags[current]; ok && current != resourceType {
+			continue
+		}
+		resolved[current] = enabled
+	}
+	return resolved
+}
+
+func resolveAlias(resourceType string, aliases map[string]string) string {
+	current, ok := aliases[resourceType]
+	if !ok {
+		return resourceType
+	}
+
+	log.Warnf("The resource type %s is deprecated, use %s instead.", resourceType, current)
+	return current
+}
+
 func IsTrue(s string) bool {
 	return strings.TrimSpace(strings.ToLower(s)) == "true"
 }
@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"reflect"
 	"sort"
 	"testing"
 
+	"github.com/dshelley66/gcp-nuke/pkg/config"
 	"github.com/dshelley66/gcp-nuke/pkg/types"
+	"github.com/dshelley66/gcp-nuke/resources"
 )
 
 func TestResolveResourceTypes(t *testing.T) {
@@ -78,6 +81,64 @@ func TestResolveResourceTypes(t *testing.T) {
 	}
 }
 
+func TestResolveAliases(t *testing.T) {
+	aliases := map[string]string{"StorageBucket": "Bucket"}
+
+	have := ResolveAliases(types.Collection{"VPC", "StorageBucket"}, aliases)
+	want := types.Collection{"VPC", "Bucket"}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("Wrong types. Want: %v. Have: %v", want, have)
+	}
+
+	empty := ResolveAliases(types.Collection{}, aliases)
+	if empty == nil || len(empty) != 0 {
+		t.Errorf("Wrong types. Want: []. Have: %#v", empty)
+	}
+}
+
+func TestRegisteredAliases(t *testing.T) {
+	// Every alias must point to a registered type, otherwise the resolved
+	// targets would silently match nothing.
+	for alias, name := range resources.GetAliasMapping() {
+		if resources.GetLister(name) == nil {
+			t.Errorf("The alias %s refers to the unknown type %s.", alias, name)
+		}
+	}
+}
+
+func TestResolveFilterAliases(t *testing.T) {
+	aliases := map[string]string{"StorageBucket": "Bucket"}
+	filters := config.Filters{
+		"Bucket":        {{Property: "Name", Value: "a"}},
+		"StorageBucket": {{Property: "Name", Value: "b"}},
+	}
+
+	have := ResolveFilterAliases(filters, aliases)
+	if len(have) != 1 || len(have["Bucket"]) != 2 {
+		t.Errorf("Wrong filters. Want: 2 filters for Bucket. Have: %v", have)
+	}
+}
+
+func TestResolveFlagAliases(t *testing.T) {
+	aliases := map[string]string{"StorageBucket": "Bucket", "Disk": "ComputeDisk"}
+	flags := config.DisableDeletionProtection{
+		"Bucket":          false,
+		"StorageBucket":   true,
+		"Disk":            true,
+		"ComputeInstance": true,
+	}
+
+	have := ResolveFlagAliases(flags, aliases)
+	want := config.DisableDeletionProtection{
+		"Bucket":          false,
+		"ComputeDisk":     true,
+		"ComputeInstance": true,
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("Wrong flags. Want: %v. Have: %v", want, have)
+	}
+}
+
 func TestIsTrue(t *testing.T) {
 	falseStrings := []string{"", "false", "treu", "foo"}
 	for _, fs := range falseStrings {
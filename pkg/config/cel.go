@@ -0,0 +1,166 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dshelley66/gcp-nuke/pkg/types"
+	"github.com/google/cel-go/cel"
+	celtypes "github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+)
+
+// CELCostLimit stops expressions which would take too long, eg nested
+// comprehensions over all properties.
+const CELCostLimit = 100000
+
+// CELCreationProperty is the property which the age of a resource is
+// calculated from.
+const CELCreationProperty = "CreationDate"
+
+var (
+	celEnv     *cel.Env
+	celEnvErr  error
+	celEnvOnce sync.Once
+)
+
+// celEnvironment declares the variables which expressions can use:
+//
+//   - properties: all properties, date-like ones as timestamps.
+//   - labels: the labels of the resource without the "tag:" prefix. Missing
+//     labels are empty strings.
+//   - now: the time of the evaluation.
+//   - age: the time since the creation of the resource.
+func celEnvironment() (*cel.Env, error) {
+	celEnvOnce.Do(func() {
+		celEnv, celEnvErr = cel.NewEnv(
+			cel.Variable("properties", cel.MapType(cel.StringType, cel.DynType)),
+			cel.Variable("labels", cel.MapType(cel.StringType, cel.StringType)),
+			cel.Variable("now", cel.TimestampType),
+			cel.Variable("age", cel.DurationType),
+		)
+	})
+	return celEnv, celEnvErr
+}
+
+// compileCEL checks an expression and prepares it for the evaluation.
+func compileCEL(expression string) (cel.Program, error) {
+	env, err := celEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid expression '%s': %v", expression, issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("invalid expression '%s': it must return a bool, but returns %s",
+			expression, ast.OutputType())
+	}
+
+	return env.Program(ast, cel.CostLimit(CELCostLimit))
+}
+
+type celResult struct {
+	program cel.Program
+	err     error
+}
+
+var (
+	celCache   = map[string]celResult{}
+	celCacheMu sync.Mutex
+)
+
+// cachedCEL compiles an expression once. Filters are only compiled when they
+// are validated or matched, which is after the variables of the config got
+// expanded, so the cache is keyed by the expanded expression.
+func cachedCEL(expression string) (cel.Program, error) {
+	celCacheMu.Lock()
+	defer celCacheMu.Unlock()
+
+	result, ok := celCache[expression]
+	if !ok {
+		result.program, result.err = compileCEL(expression)
+		celCache[expression] = result
+	}
+	return result.program, result.err
+}
+
+func (f Filter) matchCEL(expression string, properties types.Properties) (bool, error) {
+	program, err := cachedCEL(expression)
+	if err != nil {
+		return false, err
+	}
+
+	out, _, err := program.Eval(celActivation(properties, time.Now()))
+	if err != nil {
+		return false, fmt.Errorf("evaluating '%s' failed: %w", expression, err)
+	}
+
+	match, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("evaluating '%s' returned %v instead of a bool", expression, out)
+	}
+	return match, nil
+}
+
+func celActivation(properties types.Properties, now time.Time) map[string]interface{} {
+	values := map[string]interface{}{}
+	labels := map[string]string{}
+
+	for key, value := range properties {
+		if label, ok := strings.CutPrefix(key, "tag:"); ok {
+			labels[label] = value
+		}
+
+		values[key] = value
+		if isDateProperty(key) {
+			if t, err := parseDate(value); err == nil {
+				values[key] = t
+			}
+		}
+	}
+
+	activation := map[string]interface{}{
+		"properties": values,
+		"labels":     labelMap{celtypes.NewStringStringMap(celtypes.DefaultTypeAdapter, labels)},
+		"now":        now,
+	}
+
+	// Without a creation date the age is unknown, so expressions using it
+	// fail instead of deciding on a made up age.
+	if created, ok := values[CELCreationProperty].(time.Time); ok {
+		activation["age"] = now.Sub(created)
+	}
+
+	return activation
+}
+
+func isDateProperty(key string) bool {
+	return strings.HasSuffix(key, "Date") || strings.HasSuffix(key, "Time") ||
+		strings.HasSuffix(key, "Timestamp")
+}
+
+// labelMap reads missing labels as empty strings, so expressions like
+// labels["owner"] == "" work for unlabeled resources. Whether a label exists
+// can still be checked with the in operator.
+type labelMap struct {
+	traits.Mapper
+}
+
+func (m labelMap) Find(key ref.Val) (ref.Val, bool) {
+	value, found := m.Mapper.Find(key)
+	if !found && key.Type() == celtypes.StringType {
+		return celtypes.String(""), true
+	}
+	return value, found
+}
+
+func (m labelMap) Get(key ref.Val) ref.Val {
+	value, _ := m.Find(key)
+	return value
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+var (
+	// numberPattern matches plain decimal numbers, so NaN, Inf and hex floats
+	// are not compared as numbers.
+	numberPattern = regexp.MustCompile(`^[+-]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][+-]?[0-9]+)?$`)
+
+	// dottedPattern matches values like 1.10, which are versions rather than
+	// decimals.
+	dottedPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)+$`)
+)
+
+func isNumber(value string) bool {
+	return numberPattern.MatchString(value) && !dottedPattern.MatchString(value)
+}
+
+// compareValues compares two numbers or two semantic versions. Versions may
+// omit the "v" prefix, eg "1.27.3-gke.100". Values with dots are compared as
+// versions, so 1.10 is greater than 1.9.
+func compareValues(a, b string) (int, error) {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+
+	if isNumber(a) && isNumber(b) {
+		x, errA := strconv.ParseFloat(a, 64)
+		y, errB := strconv.ParseFloat(b, 64)
+		if errA != nil || errB != nil {
+			return 0, fmt.Errorf("cannot compare '%s' with '%s', expected numbers or versions", a, b)
+		}
+		switch {
+		case x < y:
+			return -1, nil
+		case x > y:
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	v, w := version(a), version(b)
+	if semver.IsValid(v) && semver.IsValid(w) {
+		return semver.Compare(v, w), nil
+	}
+
+	return 0, fmt.Errorf("cannot compare '%s' with '%s', expected numbers or versions", a, b)
+}
+
+func version(value string) string {
+	if strings.HasPrefix(value, "v") {
+		return value
+	}
+	return "v" + value
+}
+
+func isComparable(value string) bool {
+	_, err := compareValues(value, value)
+	return err == nil
+}
+
+func (f Filter) matchComparison(value string, o string) (bool, error) {
+	if o == "" {
+		return false, nil
+	}
+
+	c, err := compareValues(o, value)
+	if err != nil {
+		return false, err
+	}
+
+	switch f.Type {
+	case FilterTypeGreater:
+		return c > 0, nil
+	case FilterTypeGreaterOrEqual:
+		return c >= 0, nil
+	case FilterTypeLess:
+		return c < 0, nil
+	case FilterTypeLessOrEqual:
+		return c <= 0, nil
+	}
+
+	return false, fmt.Errorf("unknown type %s", f.Type)
+}
+
+// matchBetween matches values within the first and the second value of the
+// filter, including both.
+func (f Filter) matchBetween(o string) (bool, error) {
+	values := f.values()
+	if len(values) != 2 {
+		return false, fmt.Errorf("between requires two values, got %d", len(values))
+	}
+	if o == "" {
+		return false, nil
+	}
+
+	low, err := compareValues(o, values[0])
+	if err != nil {
+		return false, err
+	}
+	high, err := compareValues(o, values[1])
+	if err != nil {
+		return false, err
+	}
+	return low >= 0 && high <= 0, nil
+}
+
+func (f Filter) validateBetween() error {
+	values := f.values()
+	if len(values) != 2 {
+		return fmt.Errorf("between requires two values, got %d", len(values))
+	}
+
+	c, err := compareValues(values[0], values[1])
+	if err != nil {
+		return err
+	}
+	if c > 0 {
+		return fmt.Errorf("the lower bound '%s' of between is greater than the upper bound '%s'", values[0], values[1])
+	}
+	return nil
+}
+
+// parsePrefix parses a CIDR block or a single address, which is treated as
+// block of one address.
+func parsePrefix(value string) (netip.Prefix, error) {
+	value = strings.TrimSpace(value)
+
+	prefix, err := netip.ParsePrefix(value)
+	if err == nil {
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err == nil {
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+
+	return netip.Prefix{}, fmt.Errorf("'%s' is no IP address or CIDR block", value)
+}
+
+// matchCIDR matches addresses and ranges within the block of the filter.
+// Properties with several ranges are separated by commas and match if one of
+// their ranges matches.
+func matchCIDR(value string, o string) (bool, error) {
+	block, err := parsePrefix(value)
+	if err != nil {
+		return false, err
+	}
+
+	ranges := strings.FieldsFunc(o, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	for _, r := range ranges {
+		prefix, err := parsePrefix(r)
+		if err != nil {
+			return false, err
+		}
+		if prefix.Bits() >= block.Bits() && block.Contains(prefix.Addr()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Files returns all config files in the order they are merged. Files listed
+// in include come before the file including them, globs are expanded in
+// lexical order and every file is only used once.
+func Files(vars Variables, paths ...string) ([]string, error) {
+	return (&Loader{Variables: vars}).Files(paths...)
+}
+
+// Files works like the function Files, but reads the config files with the
+// loader.
+func (l *Loader) Files(paths ...string) ([]string, error) {
+	r := &includeResolver{
+		loader: l,
+		seen:   map[string]bool{},
+		active: map[string]bool{},
+	}
+
+	for _, path := range paths {
+		err := r.resolve(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return r.files, nil
+}
+
+type includeResolver struct {
+	loader *Loader
+	seen   map[string]bool
+	active map[string]bool
+	files  []string
+}
+
+func (r *includeResolver) resolve(path string) error {
+	path = clean(path)
+	if r.active[path] {
+		return fmt.Errorf("%s: The config file includes itself.", path)
+	}
+	if r.seen[path] {
+		return nil
+	}
+
+	r.active[path] = true
+	defer delete(r.active, path)
+
+	raw, err := r.loader.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var head struct {
+		Include []Include `yaml:"include"`
+	}
+	err = yaml.Unmarshal(raw, &head)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	// The checksum of a pinned file does not protect the remote files it
+	// includes, so they have to be pinned, too.
+	_, pinned := r.loader.checksum(path)
+
+	for _, include := range head.Include {
+		include.Path, err = r.loader.Variables.Expand(include.Path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		matches, err := resolveInclude(path, include.Path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		if include.SHA256 != "" && (hasGlobMeta(include.Path) || len(matches) != 1) {
+			return fmt.Errorf("%s: The pinned include '%s' must refer to a single file.", path, include.Path)
+		}
+
+		for _, match := range matches {
+			match = clean(match)
+			switch {
+			case include.SHA256 != "":
+				err = r.loader.pin(match, include.SHA256)
+				if err != nil {
+					return fmt.Errorf("%s: %w", path, err)
+				}
+			case pinned && IsRemote(match):
+				return fmt.Errorf("%s: The config file is pinned, so its remote include '%s' "+
+					"has to be pinned with sha256, too.", path, include.Path)
+			}
+
+			err = r.resolve(match)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	r.seen[path] = true
+	r.files = append(r.files, path)
+	return nil
+}
+
+func hasGlobMeta(path string) bool {
+	for _, c := range path {
+		switch c {
+		case '*', '?', '[', '\\':
+			return true
+		}
+	}
+	return false
+}
+
+// Merge layers another config over this one. Lists which add safety or
+// exceptions, like the restricted list, filters and presets, are combined.
+// Everything else is replaced, if it is set in the overlay.
+func (c *Nuke) Merge(overlay *Nuke) {
+	c.ProjectRestrictedList = appendUnique(c.ProjectRestrictedList, overlay.ProjectRestrictedList...)
+	c.ResourceTypes = c.ResourceTypes.merge(overlay.ResourceTypes)
+
+	for key, project := range overlay.Projects {
+		if c.Projects == nil {
+			c.Projects = map[string]Project{}
+		}
+		c.Projects[key] = c.Projects[key].merge(project)
+	}
+
+	c.GlobalFilters = mergeFilters(c.GlobalFilters, overlay.GlobalFilters)
+
+	for name, preset := range overlay.Presets {
+		if c.Presets == nil {
+			c.Presets = map[string]PresetDefinitions{}
+		}
+		c.Presets[name] = PresetDefinitions{
+			Filters: mergeFilters(c.Presets[name].Filters, preset.Filters),
+		}
+	}
+
+	c.FeatureFlags = c.FeatureFlags.merge(overlay.FeatureFlags)
+
+	for api, limit := range overlay.RemovalParallelism {
+		if c.RemovalParallelism == nil {
+			c.RemovalParallelism = map[string]int{}
+		}
+		c.RemovalParallelism[api] = limit
+	}
+
+	for resourceType, budget := range overlay.RetryBudgets {
+		if c.RetryBudgets == nil {
+			c.RetryBudgets = map[string]int{}
+		}
+		c.RetryBudgets[resourceType] = budget
+	}
+
+	c.CustomEndpoints = append(c.CustomEndpoints, overlay.CustomEndpoints...)
+}
+
+func (p Project) merge(overlay Project) Project {
+	if len(overlay.Locations) > 0 {
+		p.Locations = overlay.Locations
+	}
+	p.Filters = mergeFilters(p.Filters, overlay.Filters)
+	p.ResourceTypes = p.ResourceTypes.merge(overlay.ResourceTypes)
+	p.Presets = appendUnique(p.Presets, overlay.Presets...)
+	p.FeatureFlags = p.FeatureFlags.merge(overlay.FeatureFlags)
+	return p
+}
+
+func (r ResourceTypes) merge(overlay ResourceTypes) ResourceTypes {
+	if len(overlay.Targets) > 0 {
+		r.Targets = overlay.Targets
+	}
+	if len(overlay.Excludes) > 0 {
+		r.Excludes = overlay.Excludes
+	}
+	if len(overlay.CloudControl) > 0 {
+		r.CloudControl = overlay.CloudControl
+	}
+	return r
+}
+
+// mergeFilters returns a new set of filters, so the merged ones are not
+// modified.
+func mergeFilters(base, overlay Filters) Filters {
+	if base == nil && overlay == nil {
+		return nil
+	}
+
+	merged := Filters{}
+	for _, filters := range []Filters{base, overlay} {
+		for resourceType, list := range filters {
+			merged[resourceType] = append(merged[resourceType], list...)
+		}
+	}
+	return merged
+}
+
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		if !contains(list, value) {
+			list = append(list, value)
+		}
+	}
+	return list
+}
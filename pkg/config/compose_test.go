@@ -0,0 +1,130 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFiles(t *testing.T) {
+	files, err := Files(nil,
+		"test-fixtures/compose/base.yaml",
+		"test-fixtures/compose/overlay.yaml",
+		"test-fixtures/compose/teams/web.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"test-fixtures/compose/teams/data.yaml",
+		"test-fixtures/compose/teams/web.yaml",
+		"test-fixtures/compose/base.yaml",
+		"test-fixtures/compose/overlay.yaml",
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("Wrong files. Want: %v. Have: %v", want, files)
+	}
+}
+
+func TestLoadLayers(t *testing.T) {
+	t.Setenv("STATE_BUCKET", "terraform-state")
+
+	vars := Variables{VariableProjectID: "web-sandbox"}
+	config, err := LoadWithVariables(vars,
+		"test-fixtures/compose/base.yaml",
+		"test-fixtures/compose/overlay.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantRestricted := []string{"web-production", "production-project", "data-production"}
+	if !reflect.DeepEqual(config.ProjectRestrictedList, wantRestricted) {
+		t.Errorf("Wrong restricted list. Want: %v. Have: %v", wantRestricted, config.ProjectRestrictedList)
+	}
+
+	wantProjects := map[string]Project{
+		"data-sandbox": {
+			Locations: []string{"us-west1"},
+			Presets:   []string{"common"},
+			Filters: Filters{
+				"Bucket": {{Property: "Name", Value: "${KEEP}"}},
+			},
+		},
+		"web-sandbox": {
+			Locations: []string{"europe-west1"},
+			Filters: Filters{
+				"Bucket": {{Property: "Name", Value: "web-sandbox-assets"}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(config.Projects, wantProjects) {
+		t.Errorf("Wrong projects.\nWant: %#v\nHave: %#v", wantProjects, config.Projects)
+	}
+
+	have := config.Presets["common"].Filters["Bucket"][0].Value
+	if have != "terraform-state" {
+		t.Errorf("Wrong preset filter. Want: terraform-state. Have: %s", have)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		paths []string
+		want  string
+	}{
+		{
+			name:  "cycle",
+			paths: []string{"test-fixtures/compose/cycle.yaml"},
+			want:  "test-fixtures/compose/cycle.yaml: The config file includes itself.",
+		},
+		{
+			name:  "missing include",
+			paths: []string{"test-fixtures/compose/missing.yaml"},
+			want:  "test-fixtures/compose/missing.yaml: The included file 'teams/none.yaml' does not exist.",
+		},
+		{
+			name:  "undefined variable",
+			paths: []string{"test-fixtures/compose/teams/web.yaml"},
+			want:  "test-fixtures/compose/teams/web.yaml: The variable 'PROJECT_ID' is not defined.",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load(tc.paths...)
+			if err == nil {
+				t.Fatal("Expected an error but didn't get one.")
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Wrong error. Want: %s. Have: %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestValidateIncludes(t *testing.T) {
+	t.Setenv("STATE_BUCKET", "terraform-state")
+
+	catalog := Catalog{
+		ResourceTypes: map[string]ResourceTypeInfo{
+			"Bucket": {Properties: []string{"Name"}},
+		},
+	}
+	vars := Variables{VariableProjectID: "web-production"}
+
+	problems, err := Validate(catalog, vars, "test-fixtures/compose/base.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Problem{{
+		File:    "test-fixtures/compose/teams/web.yaml",
+		Line:    6,
+		Path:    "projects.web-production",
+		Message: "the project is in the project-restricted-list and cannot be nuked",
+	}}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("Wrong problems. Want: %v. Have: %v", want, problems)
+	}
+}
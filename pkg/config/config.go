@@ -2,7 +2,7 @@ package config
 
 import (
 	"fmt"
-	"os"
+	"net/url"
 
 	"github.com/dshelley66/gcp-nuke/pkg/types"
 	"gopkg.in/yaml.v2"
@@ -19,22 +19,72 @@ type Project struct {
 	Filters       Filters       `yaml:"filters"`
 	ResourceTypes ResourceTypes `yaml:"resource-types"`
 	Presets       []string      `yaml:"presets"`
+	FeatureFlags  FeatureFlags  `yaml:"feature-flags"`
 }
 
 type Nuke struct {
+	Include               []Include                    `yaml:"include"`
 	ProjectRestrictedList []string                     `yaml:"project-restricted-list"`
 	Projects              map[string]Project           `yaml:"projects"`
+	GlobalFilters         Filters                      `yaml:"filters"`
 	ResourceTypes         ResourceTypes                `yaml:"resource-types"`
 	Presets               map[string]PresetDefinitions `yaml:"presets"`
 	FeatureFlags          FeatureFlags                 `yaml:"feature-flags"`
+	RemovalParallelism    map[string]int               `yaml:"removal-parallelism"`
+	RetryBudgets          map[string]int               `yaml:"retry-budgets"`
+	CustomEndpoints       CustomEndpoints              `yaml:"endpoints"`
 }
 
 type FeatureFlags struct {
 	DisableDeletionProtection DisableDeletionProtection `yaml:"disable-deletion-protection"`
+
+	// ScopeStorageLocations limits buckets, bucket objects and BigQuery
+	// datasets and jobs to the configured locations. They belong to global
+	// services, so by default they are listed regardless of their location.
+	ScopeStorageLocations *bool `yaml:"scope-storage-locations"`
+}
+
+// StorageLocationsScoped reports whether the scope-storage-locations flag is
+// enabled.
+func (f FeatureFlags) StorageLocationsScoped() bool {
+	return f.ScopeStorageLocations != nil && *f.ScopeStorageLocations
+}
+
+// merge returns a new set of flags, in which the flags of the overlay take
+// precedence.
+func (f FeatureFlags) merge(overlay FeatureFlags) FeatureFlags {
+	merged := FeatureFlags{
+		DisableDeletionProtection: f.DisableDeletionProtection.merge(overlay.DisableDeletionProtection),
+		ScopeStorageLocations:     f.ScopeStorageLocations,
+	}
+	if overlay.ScopeStorageLocations != nil {
+		merged.ScopeStorageLocations = overlay.ScopeStorageLocations
+	}
+	return merged
+}
+
+// DisableDeletionProtection enables the preparation of resources per type,
+// like removing their deletion protection or holds, before removing them.
+type DisableDeletionProtection map[string]bool
+
+func (d DisableDeletionProtection) Enabled(resourceType string) bool {
+	return d[resourceType]
 }
 
-type DisableDeletionProtection struct {
-	ComputeInstance bool `yaml:"ComputeInstance"`
+// merge returns a new set of flags, in which the flags of the overlay take
+// precedence.
+func (d DisableDeletionProtection) merge(overlay DisableDeletionProtection) DisableDeletionProtection {
+	if d == nil && overlay == nil {
+		return nil
+	}
+
+	merged := DisableDeletionProtection{}
+	for _, flags := range []DisableDeletionProtection{d, overlay} {
+		for resourceType, enabled := range flags {
+			merged[resourceType] = enabled
+		}
+	}
+	return merged
 }
 
 type PresetDefinitions struct {
@@ -57,10 +107,99 @@ type CustomRegion struct {
 
 type CustomEndpoints []*CustomRegion
 
-func Load(path string) (*Nuke, error) {
-	var err error
+// Services returns the custom services which apply to a run in the given
+// locations by their API name. Regions without name or the region "global"
+// apply to every run. The TLS settings of a region apply to all its
+// services.
+func (e CustomEndpoints) Services(locations []string) (map[string]CustomService, error) {
+	services := map[string]CustomService{}
+
+	for _, region := range e {
+		if region.Region != "" && region.Region != "global" && !contains(locations, region.Region) {
+			continue
+		}
+
+		for _, service := range region.Services {
+			err := service.Validate()
+			if err != nil {
+				return nil, err
+			}
+
+			merged := *service
+			merged.TLSInsecureSkipVerify = service.TLSInsecureSkipVerify || region.TLSInsecureSkipVerify
+
+			existing, ok := services[service.Service]
+			if ok && existing != merged {
+				return nil, fmt.Errorf("The endpoint of the service '%s' is configured differently "+
+					"for several regions of the run.", service.Service)
+			}
+			services[service.Service] = merged
+		}
+	}
+
+	return services, nil
+}
+
+// Validate checks the URL of the custom service, which has to be an http or
+// https URL. Without TLS no credentials are sent, which is meant for
+// emulators.
+func (s *CustomService) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return fmt.Errorf("The endpoint of the service '%s' is invalid: %v", s.Service, err)
+	}
+	if u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("The endpoint of the service '%s' must be an http or https URL, "+
+			"but is '%s'.", s.Service, s.URL)
+	}
+	return nil
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Load reads the given config files, including the files they include, and
+// merges them in order. Variables are only looked up in the environment.
+func Load(paths ...string) (*Nuke, error) {
+	return LoadWithVariables(nil, paths...)
+}
+
+// LoadWithVariables works like Load, but looks up variables in the given
+// ones before the environment.
+func LoadWithVariables(vars Variables, paths ...string) (*Nuke, error) {
+	return (&Loader{Variables: vars}).Load(paths...)
+}
+
+// Load reads and merges the config files with the loader. It fails, if any
+// of the files cannot be read, so a run never continues without parts of its
+// config.
+func (l *Loader) Load(paths ...string) (*Nuke, error) {
+	files, err := l.Files(paths...)
+	if err != nil {
+		return nil, err
+	}
+
+	config := new(Nuke)
+	for _, path := range files {
+		layer, err := l.loadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		config.Merge(layer)
+	}
+
+	return config, nil
+}
 
-	raw, err := os.ReadFile(path)
+func (l *Loader) loadFile(path string) (*Nuke, error) {
+	raw, err := l.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
@@ -71,6 +210,14 @@ func Load(path string) (*Nuke, error) {
 		return nil, err
 	}
 
+	// The includes were already resolved.
+	config.Include = nil
+
+	err = l.Variables.ExpandAll(config)
+	if err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
@@ -78,9 +225,13 @@ func (c *Nuke) HasRestrictedList() bool {
 	return c.ProjectRestrictedList != nil && len(c.ProjectRestrictedList) > 0
 }
 
+// InBlocklist checks whether the project matches any entry of the
+// project-restricted-list. Invalid patterns match every project, so a typo
+// never exposes a restricted project.
 func (c *Nuke) InBlocklist(searchID string) bool {
 	for _, restrictedProject := range c.ProjectRestrictedList {
-		if restrictedProject == searchID {
+		match, err := ProjectPattern(restrictedProject).Match(searchID)
+		if match || err != nil {
 			return true
 		}
 	}
@@ -88,6 +239,32 @@ func (c *Nuke) InBlocklist(searchID string) bool {
 	return false
 }
 
+// MatchProject returns the key of the projects map, whose config applies to
+// the project, or an empty string if none matches. If several keys match,
+// the one with the highest precedence is used.
+func (c *Nuke) MatchProject(projectID string) (string, error) {
+	var best ProjectPattern
+	found := false
+
+	for key := range c.Projects {
+		pattern := ProjectPattern(key)
+		match, err := pattern.Match(projectID)
+		if err != nil {
+			return "", fmt.Errorf("The project pattern '%s' is invalid: %v", key, err)
+		}
+		if !match {
+			continue
+		}
+
+		if !found || pattern.precedes(best) {
+			best = pattern
+			found = true
+		}
+	}
+
+	return string(best), nil
+}
+
 func (c *Nuke) ValidateProject(projectID string) error {
 	if !c.HasRestrictedList() {
 		return fmt.Errorf("The config file contains an empty restricted list. " +
@@ -95,12 +272,23 @@ func (c *Nuke) ValidateProject(projectID string) error {
 			"This should be your production account.")
 	}
 
+	for _, restrictedProject := range c.ProjectRestrictedList {
+		err := ProjectPattern(restrictedProject).Validate()
+		if err != nil {
+			return fmt.Errorf("The restricted list contains an %v. Aborting.", err)
+		}
+	}
+
 	if c.InBlocklist(projectID) {
 		return fmt.Errorf("You are trying to nuke the project with the ID %s, "+
 			"but it is restricted. Aborting.", projectID)
 	}
 
-	if _, ok := c.Projects[projectID]; !ok {
+	key, err := c.MatchProject(projectID)
+	if err != nil {
+		return err
+	}
+	if key == "" {
 		return fmt.Errorf("Your project '%s' isn't listed in the config. "+
 			"Aborting.", projectID)
 	}
@@ -108,9 +296,19 @@ func (c *Nuke) ValidateProject(projectID string) error {
 	return nil
 }
 
+// Filters returns the filters of a project, which consist of the global
+// filters, the filters of the project and the filters of its presets. Each
+// filter records its origin in the config.
 func (c *Nuke) Filters(accountID string) (Filters, error) {
-	account := c.Projects[accountID]
-	filters := account.Filters
+	key, err := c.MatchProject(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	account := c.Projects[key]
+	filters := mergeFilters(
+		c.GlobalFilters.withOrigin("filters", ""),
+		account.Filters.withOrigin(fmt.Sprintf("projects.%s.filters", key), ""))
 
 	if filters == nil {
 		filters = Filters{}
@@ -131,8 +329,21 @@ func (c *Nuke) Filters(accountID string) (Filters, error) {
 			return nil, notFound
 		}
 
-		filters.Merge(preset.Filters)
+		filters.Merge(preset.Filters.withOrigin(fmt.Sprintf("presets.%s.filters", presetName), presetName))
 	}
 
 	return filters, nil
 }
+
+// ProjectFeatureFlags resolves the feature flags of a project. A flag set for
+// the project takes precedence over the global one. Flags which are set
+// nowhere are disabled.
+func (c *Nuke) ProjectFeatureFlags(projectID string) (FeatureFlags, error) {
+	key, err := c.MatchProject(projectID)
+	if err != nil {
+		return FeatureFlags{}, err
+	}
+
+	project := c.Projects[key]
+	return c.FeatureFlags.merge(project.FeatureFlags), nil
+}
@@ -73,6 +73,11 @@ func TestLoadExampleConfig(t *testing.T) {
 			Targets:  types.Collection{"DynamoDBTable", "S3Bucket", "S3Object"},
 			Excludes: types.Collection{"IAMRole"},
 		},
+		FeatureFlags: FeatureFlags{
+			DisableDeletionProtection: DisableDeletionProtection{
+				"ComputeInstance": true,
+			},
+		},
 		Presets: map[string]PresetDefinitions{
 			"terraform": {
 				Filters: Filters{
@@ -142,18 +147,21 @@ func TestFilterMerge(t *testing.T) {
 		"S3Bucket": []Filter{
 			{
 				Type: "glob", Value: "my-statebucket-*",
+				Origin: FilterOrigin{Path: "presets.terraform.filters.S3Bucket[0]", Preset: "terraform"},
 			},
 		},
 		"IAMRole": []Filter{
 			{
-				Type:  "exact",
-				Value: "uber.admin",
+				Type:   "exact",
+				Value:  "uber.admin",
+				Origin: FilterOrigin{Path: "projects.gcp-test-project.filters.IAMRole[0]"},
 			},
 		},
 		"IAMRolePolicyAttachment": []Filter{
 			{
-				Type:  "exact",
-				Value: "uber.admin -> AdministratorAccess",
+				Type:   "exact",
+				Value:  "uber.admin -> AdministratorAccess",
+				Origin: FilterOrigin{Path: "projects.gcp-test-project.filters.IAMRolePolicyAttachment[0]"},
 			},
 		},
 	}
@@ -164,3 +172,161 @@ func TestFilterMerge(t *testing.T) {
 		t.Errorf("  Expected: %#v", expect)
 	}
 }
+
+func TestCustomEndpointsServices(t *testing.T) {
+	endpoints := CustomEndpoints{
+		{
+			Services: CustomServices{
+				{Service: "storage", URL: "http://localhost:4443/storage/v1/"},
+			},
+		},
+		{
+			Region:                "us-east1",
+			TLSInsecureSkipVerify: true,
+			Services: CustomServices{
+				{Service: "compute", URL: "https://compute.example.com/compute/v1/"},
+			},
+		},
+		{
+			Region: "europe-west1",
+			Services: CustomServices{
+				{Service: "pubsub", URL: "https://pubsub.example.com"},
+			},
+		},
+	}
+
+	services, err := endpoints.Services([]string{"global", "us-east1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]CustomService{
+		"storage": {Service: "storage", URL: "http://localhost:4443/storage/v1/"},
+		"compute": {Service: "compute", URL: "https://compute.example.com/compute/v1/", TLSInsecureSkipVerify: true},
+	}
+	if !reflect.DeepEqual(services, want) {
+		t.Errorf("Wrong services. Want: %v. Have: %v", want, services)
+	}
+
+	endpoints = append(endpoints, &CustomRegion{
+		Region: "global",
+		Services: CustomServices{
+			{Service: "compute", URL: "https://other.example.com/compute/v1/"},
+		},
+	})
+	_, err = endpoints.Services([]string{"us-east1"})
+	if err == nil {
+		t.Errorf("Expected an error for conflicting endpoints.")
+	}
+}
+
+func TestProjectFeatureFlags(t *testing.T) {
+	config := &Nuke{
+		FeatureFlags: FeatureFlags{
+			DisableDeletionProtection: DisableDeletionProtection{
+				"ComputeInstance": true,
+				"CloudSQL":        true,
+			},
+		},
+		Projects: map[string]Project{
+			"ci-*": {
+				FeatureFlags: FeatureFlags{
+					DisableDeletionProtection: DisableDeletionProtection{
+						"CloudSQL":     false,
+						"BucketObject": true,
+					},
+				},
+			},
+			"staging": {},
+		},
+	}
+
+	cases := []struct {
+		project string
+		want    map[string]bool
+	}{
+		{"ci-pr-1", map[string]bool{"ComputeInstance": true, "CloudSQL": false, "BucketObject": true}},
+		{"staging", map[string]bool{"ComputeInstance": true, "CloudSQL": true, "BucketObject": false}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.project, func(t *testing.T) {
+			flags, err := config.ProjectFeatureFlags(tc.project)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for resourceType, want := range tc.want {
+				have := flags.DisableDeletionProtection.Enabled(resourceType)
+				if have != want {
+					t.Errorf("Wrong flag for %s. Want: %t. Have: %t", resourceType, want, have)
+				}
+			}
+		})
+	}
+
+	// The global flags must not be changed by resolving the project flags.
+	if !config.FeatureFlags.DisableDeletionProtection.Enabled("CloudSQL") {
+		t.Errorf("The global flags got changed.")
+	}
+}
+
+func TestScopeStorageLocations(t *testing.T) {
+	enabled, disabled := true, false
+	config := &Nuke{
+		FeatureFlags: FeatureFlags{ScopeStorageLocations: &enabled},
+		Projects: map[string]Project{
+			"ci-*":    {FeatureFlags: FeatureFlags{ScopeStorageLocations: &disabled}},
+			"staging": {},
+		},
+	}
+
+	for project, want := range map[string]bool{"ci-pr-1": false, "staging": true} {
+		flags, err := config.ProjectFeatureFlags(project)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have := flags.StorageLocationsScoped(); have != want {
+			t.Errorf("Wrong flag for %s. Want: %t. Have: %t", project, want, have)
+		}
+	}
+
+	if (FeatureFlags{}).StorageLocationsScoped() {
+		t.Errorf("The storage locations are scoped by default.")
+	}
+}
+
+func TestGlobalFilters(t *testing.T) {
+	config := &Nuke{
+		GlobalFilters: Filters{
+			"Bucket": {NewExactFilter("terraform-state")},
+		},
+		Projects: map[string]Project{
+			"sandbox": {
+				Filters: Filters{
+					"Bucket": {NewExactFilter("sandbox-assets")},
+				},
+			},
+		},
+	}
+
+	filters, err := config.Filters("sandbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	global := NewExactFilter("terraform-state")
+	global.Origin = FilterOrigin{Path: "filters.Bucket[0]"}
+	project := NewExactFilter("sandbox-assets")
+	project.Origin = FilterOrigin{Path: "projects.sandbox.filters.Bucket[0]"}
+
+	want := Filters{
+		"Bucket": {global, project},
+	}
+	if !reflect.DeepEqual(filters, want) {
+		t.Errorf("Wrong filters. Want: %v. Have: %v", want, filters)
+	}
+	if len(config.GlobalFilters["Bucket"]) != 1 {
+		t.Errorf("The global filters got changed.")
+	}
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UnparsableDate decides how date filters treat values which are no date.
+type UnparsableDate string
+
+const (
+	// UnparsableDateError fails the run, since the resource cannot be
+	// checked. This is the default.
+	UnparsableDateError    UnparsableDate = "error"
+	UnparsableDateMatch    UnparsableDate = "match"
+	UnparsableDateMismatch UnparsableDate = "mismatch"
+)
+
+// dateFormats lists the formats of the dates emitted by the resources. Zones
+// in the dates are kept, dates without zone are UTC.
+var dateFormats = []string{
+	"2006-01-02",
+	"2006/01/02",
+	"2006-01-02T15:04:05Z",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05.000 -0700 MST",       // Date format used by AWS for CreateTime on ASGs
+	"2006-01-02 15:04:05.999999999 -0700 MST", // Format of time.Time.String()
+	time.RFC3339Nano,                          // Format of t.MarshalText() and t.MarshalJSON()
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// protoTimestamp matches the text format of protobuf timestamps, eg
+// "seconds:1672628645 nanos:678000000".
+var protoTimestamp = regexp.MustCompile(`^seconds:\s*(-?[0-9]+)(?:\s+nanos:\s*([0-9]+))?$`)
+
+func parseDate(input string) (time.Time, error) {
+	input = strings.TrimSpace(input)
+
+	if i, err := strconv.ParseInt(input, 10, 64); err == nil {
+		t := time.Unix(i, 0)
+		return t, nil
+	}
+
+	if m := protoTimestamp.FindStringSubmatch(input); m != nil {
+		seconds, _ := strconv.ParseInt(m[1], 10, 64)
+		nanos, _ := strconv.ParseInt(m[2], 10, 64)
+		return time.Unix(seconds, nanos), nil
+	}
+
+	// time.Time.String() appends the monotonic clock reading.
+	if i := strings.Index(input, " m="); i >= 0 {
+		input = input[:i]
+	}
+
+	for _, f := range dateFormats {
+		t, err := time.Parse(f, input)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Now(), fmt.Errorf("unable to parse time %s", input)
+}
+
+// dayUnits matches durations in days or weeks, which time.ParseDuration does
+// not know.
+var dayUnits = regexp.MustCompile(`([0-9]*\.?[0-9]+)([dw])`)
+
+// parseDuration works like time.ParseDuration, but also accepts days (7d)
+// and weeks (2w).
+func parseDuration(input string) (time.Duration, error) {
+	expanded := dayUnits.ReplaceAllStringFunc(input, func(s string) string {
+		m := dayUnits.FindStringSubmatch(s)
+		n, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return s
+		}
+
+		hours := n * 24
+		if m[2] == "w" {
+			hours = hours * 7
+		}
+		return strconv.FormatFloat(hours, 'f', -1, 64) + "h"
+	})
+
+	d, err := time.ParseDuration(expanded)
+	if err != nil && expanded != input {
+		// The error would show the expanded duration otherwise.
+		return 0, fmt.Errorf("time: invalid duration %q", input)
+	}
+	return d, err
+}
+
+func (f Filter) matchDate(value string, o string) (bool, error) {
+	if o == "" {
+		return false, nil
+	}
+
+	date, err := parseDate(o)
+	if err != nil {
+		switch f.Unparsable {
+		case UnparsableDateMatch:
+			return true, nil
+		case UnparsableDateMismatch:
+			return false, nil
+		default:
+			return false, err
+		}
+	}
+
+	now := time.Now()
+
+	// All date types are named after the resources which are nuked, not
+	// after the ones which are matched, since dateOlderThan always worked
+	// like that. A match filters the resource, so eg dateOlderThan matches
+	// the resources younger than the offset and only older ones are nuked.
+	switch f.Type {
+	case FilterTypeDateOlderThan:
+		duration, err := parseDuration(value)
+		if err != nil {
+			return false, err
+		}
+		return date.Add(duration).After(now), nil
+
+	case FilterTypeDateNewerThan:
+		duration, err := parseDuration(value)
+		if err != nil {
+			return false, err
+		}
+		return !date.Add(duration).After(now), nil
+
+	case FilterTypeDateBefore:
+		limit, err := parseDate(value)
+		if err != nil {
+			return false, err
+		}
+		return !date.Before(limit), nil
+
+	case FilterTypeDateAfter:
+		limit, err := parseDate(value)
+		if err != nil {
+			return false, err
+		}
+		return !date.After(limit), nil
+	}
+
+	return false, fmt.Errorf("unknown type %s", f.Type)
+}
+
+func (f Filter) isDate() bool {
+	switch f.Type {
+	case FilterTypeDateOlderThan, FilterTypeDateNewerThan, FilterTypeDateBefore, FilterTypeDateAfter:
+		return true
+	}
+	return false
+}
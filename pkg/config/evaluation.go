@@ -0,0 +1,180 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dshelley66/gcp-nuke/pkg/types"
+)
+
+// A Target is a resource which gets filtered.
+type Target interface {
+	// GetProperty returns the value of a property. The empty property is the
+	// legacy ID.
+	GetProperty(property string) (string, error)
+
+	// GetProperties returns all properties for expressions.
+	GetProperties() (types.Properties, error)
+}
+
+// Evaluation records how a filter decided about a resource, so the decision
+// can be explained. Groups contain the evaluations of their filters.
+type Evaluation struct {
+	Filter   string
+	Value    string
+	Err      error
+	Match    bool
+	Children []Evaluation
+
+	// property is set, if a single property was matched.
+	property bool
+}
+
+// Evaluate matches the filter against a resource. All filters of groups are
+// evaluated, so the whole tree can be shown. A property which cannot be read
+// does not match.
+func (f Filter) Evaluate(target Target) (Evaluation, error) {
+	e := Evaluation{Filter: f.describe()}
+
+	switch {
+	case f.All != nil:
+		e.Match = true
+		for _, child := range f.All {
+			c, err := child.Evaluate(target)
+			if err != nil {
+				return e, err
+			}
+			e.Match = e.Match && c.Match
+			e.Children = append(e.Children, c)
+		}
+
+	case f.Any != nil:
+		for _, child := range f.Any {
+			c, err := child.Evaluate(target)
+			if err != nil {
+				return e, err
+			}
+			e.Match = e.Match || c.Match
+			e.Children = append(e.Children, c)
+		}
+
+	case f.Not != nil:
+		c, err := f.Not.Evaluate(target)
+		if err != nil {
+			return e, err
+		}
+		e.Match = !c.Match
+		e.Children = append(e.Children, c)
+
+	case f.Type == FilterTypeCEL:
+		properties, err := target.GetProperties()
+		if err != nil {
+			e.Err = err
+			return e, nil
+		}
+
+		for _, expression := range f.values() {
+			e.Match, err = f.matchCEL(expression, properties)
+			if err != nil || e.Match {
+				break
+			}
+		}
+		if err != nil {
+			return e, err
+		}
+
+	default:
+		value, err := target.GetProperty(f.Property)
+		if err != nil {
+			e.Err = err
+			return e, nil
+		}
+		e.Value = value
+		e.property = true
+
+		e.Match, err = f.Match(value)
+		if err != nil {
+			return e, err
+		}
+	}
+
+	if f.Invert {
+		e.Match = !e.Match
+	}
+	return e, nil
+}
+
+func (f Filter) describe() string {
+	var description string
+	switch {
+	case f.All != nil:
+		description = "all"
+	case f.Any != nil:
+		description = "any"
+	case f.Not != nil:
+		description = "not"
+	case f.Type == FilterTypeCEL:
+		description = fmt.Sprintf("cel '%s'", strings.Join(f.values(), "', '"))
+	default:
+		property := f.Property
+		if property == "" {
+			property = "ID"
+		}
+
+		filterType := f.Type
+		if filterType == FilterTypeEmpty {
+			filterType = FilterTypeExact
+		}
+
+		values := []string{}
+		for _, value := range f.values() {
+			values = append(values, fmt.Sprintf("'%s'", value))
+		}
+
+		description = fmt.Sprintf("%s %s %s", property, filterType, strings.Join(values, ", "))
+	}
+
+	if f.Invert {
+		description = description + " (inverted)"
+	}
+	return description
+}
+
+// Errors returns the errors of properties which could not be read.
+func (e Evaluation) Errors() []error {
+	errs := []error{}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	for _, child := range e.Children {
+		errs = append(errs, child.Errors()...)
+	}
+	return errs
+}
+
+// String returns the evaluation as indented tree with one filter per line.
+func (e Evaluation) String() string {
+	lines := []string{}
+	e.lines("", &lines)
+	return strings.Join(lines, "\n")
+}
+
+func (e Evaluation) lines(indent string, lines *[]string) {
+	result := "no match"
+	if e.Match {
+		result = "match"
+	}
+
+	switch {
+	case e.Err != nil:
+		*lines = append(*lines, fmt.Sprintf("%s%s: %s (%v)", indent, e.Filter, result, e.Err))
+	case e.property:
+		*lines = append(*lines, fmt.Sprintf("%s%s: %s (is '%s')", indent, e.Filter, result, e.Value))
+	default:
+		*lines = append(*lines, fmt.Sprintf("%s%s: %s", indent, e.Filter, result))
+	}
+
+	for _, child := range e.Children {
+		child.lines(indent+"  ", lines)
+	}
+}
@@ -0,0 +1,235 @@
+package config_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dshelley66/gcp-nuke/pkg/config"
+	"github.com/dshelley66/gcp-nuke/pkg/types"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// target is a resource with fixed properties.
+type target types.Properties
+
+func (t target) GetProperty(property string) (string, error) {
+	value, ok := t[property]
+	if !ok {
+		return "", fmt.Errorf("unknown property %s", property)
+	}
+	return value, nil
+}
+
+func (t target) GetProperties() (types.Properties, error) {
+	return types.Properties(t), nil
+}
+
+func TestEvaluateGroups(t *testing.T) {
+	resource := target{
+		"Name":       "data-lake",
+		"Status":     "RUNNING",
+		"tag:team":   "data",
+		"CreateTime": "2006-01-02",
+	}
+
+	cases := []struct {
+		name  string
+		yaml  string
+		match bool
+		tree  string
+	}{
+		{
+			name: "all",
+			yaml: `
+all:
+- property: tag:team
+  value: data
+- not:
+    property: Status
+    value: TERMINATED
+`,
+			match: true,
+			tree: `all: match
+  tag:team exact 'data': match (is 'data')
+  not: match
+    Status exact 'TERMINATED': no match (is 'RUNNING')`,
+		},
+		{
+			name: "any",
+			yaml: `
+any:
+- property: Name
+  type: glob
+  values: ["web-*", "api-*"]
+- property: Missing
+  value: x
+`,
+			match: false,
+			tree: `any: no match
+  Name glob 'web-*', 'api-*': no match (is 'data-lake')
+  Missing exact 'x': no match (unknown property Missing)`,
+		},
+		{
+			name: "inverted",
+			yaml: `
+any: [foo, bar]
+invert: true
+`,
+			match: true,
+			tree: `any (inverted): match
+  ID exact 'foo': no match (unknown property )
+  ID exact 'bar': no match (unknown property )`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var filter config.Filter
+			err := yaml.UnmarshalStrict([]byte(tc.yaml), &filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := filter.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if !filter.IsGroup() {
+				t.Fatalf("The filter should be a group.")
+			}
+
+			evaluation, err := filter.Evaluate(resource)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if evaluation.Match != tc.match {
+				t.Errorf("Wrong match. Want: %t. Have: %t", tc.match, evaluation.Match)
+			}
+			if evaluation.String() != tc.tree {
+				t.Errorf("Wrong tree.\nWant:\n%s\nHave:\n%s", tc.tree, evaluation)
+			}
+		})
+	}
+}
+
+func TestValidateGroups(t *testing.T) {
+	for _, input := range []string{
+		`{"all":[], "property":"Name"}`,
+		`{"all":[{"value":"a"}], "any":[{"value":"b"}]}`,
+		`{"all":[]}`,
+		`{"not":{"type":"regex","value":"("}}`,
+	} {
+		var filter config.Filter
+		err := yaml.Unmarshal([]byte(input), &filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if filter.Validate() == nil {
+			t.Errorf("Expected a validation error for %s but didn't get one.", input)
+		}
+	}
+}
+
+func TestEvaluateCEL(t *testing.T) {
+	created := time.Now().UTC().Add(-100 * time.Hour).Format(time.RFC3339)
+	resource := target{
+		"Name":         "scratch-disk",
+		"CreationDate": created,
+		"tag:team":     "data",
+		"tag:bucket:x": "y",
+	}
+
+	cases := []struct {
+		expression string
+		match      bool
+	}{
+		{`labels["owner"] == "" && age > duration("72h")`, true},
+		{`labels["team"] == "data"`, true},
+		{`"owner" in labels`, false},
+		{`labels["bucket:x"] == "y"`, true},
+		{`age > duration("168h")`, false},
+		{`properties.Name.startsWith("scratch-")`, true},
+		{`properties.CreationDate < now - duration("96h")`, true},
+		{`properties.CreationDate > timestamp("2100-01-01T00:00:00Z")`, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expression, func(t *testing.T) {
+			filter := config.Filter{}
+			input := fmt.Sprintf("{type: cel, value: %q}", tc.expression)
+			err := yaml.Unmarshal([]byte(input), &filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := filter.Validate(); err != nil {
+				t.Fatal(err)
+			}
+
+			evaluation, err := filter.Evaluate(resource)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if evaluation.Match != tc.match {
+				t.Errorf("Wrong match. Want: %t. Have: %t", tc.match, evaluation.Match)
+			}
+		})
+	}
+}
+
+func TestCELErrors(t *testing.T) {
+	for _, expression := range []string{
+		`labels["owner"] ==`,
+		`properties.Name`,
+		`unknown == 1`,
+	} {
+		filter := config.Filter{Type: config.FilterTypeCEL, Value: expression}
+		if filter.Validate() == nil {
+			t.Errorf("Expected a compile error for %s but didn't get one.", expression)
+		}
+	}
+
+	// Without a creation date the age cannot be known.
+	filter := config.Filter{Type: config.FilterTypeCEL, Value: `age > duration("1h")`}
+	_, err := filter.Evaluate(target{"Name": "foo"})
+	if err == nil {
+		t.Errorf("Expected an evaluation error without a creation date.")
+	}
+}
+
+func TestCELVariables(t *testing.T) {
+	// The unexpanded expression does not even compile.
+	t.Setenv("MIN_LENGTH", "10")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`project-restricted-list: [prod]
+filters:
+  Disk:
+  - type: cel
+    value: size(properties.Name) > ${MIN_LENGTH}
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filter := cfg.GlobalFilters["Disk"][0]
+	if err := filter.Validate(); err != nil {
+		t.Fatalf("The expanded expression is invalid: %v", err)
+	}
+
+	for name, match := range map[string]bool{"scratch-disk": true, "data": false} {
+		evaluation, err := filter.Evaluate(target{"Name": name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if evaluation.Match != match {
+			t.Errorf("Wrong match for %s. Want: %t. Have: %t", name, match, evaluation.Match)
+		}
+	}
+}
@@ -3,9 +3,7 @@ package config
 import (
 	"fmt"
 	"regexp"
-	"strconv"
 	"strings"
-	"time"
 
 	"github.com/mb0/glob"
 )
@@ -19,8 +17,40 @@ const (
 	FilterTypeRegex         FilterType = "regex"
 	FilterTypeContains      FilterType = "contains"
 	FilterTypeDateOlderThan FilterType = "dateOlderThan"
+	FilterTypeDateNewerThan FilterType = "dateNewerThan"
+	FilterTypeDateBefore    FilterType = "dateBefore"
+	FilterTypeDateAfter     FilterType = "dateAfter"
+
+	FilterTypeGreater        FilterType = "gt"
+	FilterTypeGreaterOrEqual FilterType = "gte"
+	FilterTypeLess           FilterType = "lt"
+	FilterTypeLessOrEqual    FilterType = "lte"
+	FilterTypeBetween        FilterType = "between"
+	FilterTypeCIDR           FilterType = "cidr"
+
+	FilterTypeCEL FilterType = "cel"
 )
 
+// FilterTypes lists all types a filter can have. The empty type is the same
+// as exact.
+var FilterTypes = []FilterType{
+	FilterTypeExact,
+	FilterTypeGlob,
+	FilterTypeRegex,
+	FilterTypeContains,
+	FilterTypeDateOlderThan,
+	FilterTypeDateNewerThan,
+	FilterTypeDateBefore,
+	FilterTypeDateAfter,
+	FilterTypeGreater,
+	FilterTypeGreaterOrEqual,
+	FilterTypeLess,
+	FilterTypeLessOrEqual,
+	FilterTypeBetween,
+	FilterTypeCIDR,
+	FilterTypeCEL,
+}
+
 type Filters map[string][]Filter
 
 func (f Filters) Merge(f2 Filters) {
@@ -29,75 +59,269 @@ func (f Filters) Merge(f2 Filters) {
 	}
 }
 
+// withOrigin returns a copy of the filters, which records that they are
+// configured below the given path.
+func (f Filters) withOrigin(path string, preset string) Filters {
+	if f == nil {
+		return nil
+	}
+
+	filters := Filters{}
+	for resourceType, list := range f {
+		filters[resourceType] = make([]Filter, len(list))
+		for i, filter := range list {
+			filter.Origin = FilterOrigin{
+				Path:   fmt.Sprintf("%s.%s[%d]", path, resourceType, i),
+				Preset: preset,
+				Index:  i,
+			}
+			filters[resourceType][i] = filter
+		}
+	}
+	return filters
+}
+
+// FilterOrigin tells where a filter is configured.
+type FilterOrigin struct {
+	// Path is the location in the config, like the paths of the validation
+	// problems, eg presets.terraform.filters.Bucket[0].
+	Path string
+
+	// Preset is the name of the preset, if the filter comes from one.
+	Preset string
+
+	// Index is the position of the filter in its list.
+	Index int
+}
+
 type Filter struct {
 	Property string
 	Type     FilterType
 	Value    string
-	Invert   string
+
+	// Values lists further values. The filter matches, if any of them or
+	// the value matches.
+	Values []string
+
+	Invert bool
+
+	// Unparsable decides whether date filters match values which are no
+	// date. By default they fail the run.
+	Unparsable UnparsableDate
+
+	// Description explains why the filter exists. It is shown as reason for
+	// the filtered resources.
+	Description string
+
+	// Origin tells where the filter is configured. It is set, when the
+	// filters of a project are resolved.
+	Origin FilterOrigin
+
+	// All, Any and Not make the filter a group, which combines other filters
+	// instead of matching a property itself.
+	All []Filter
+	Any []Filter
+	Not *Filter
+
+	// invalidInvert keeps an invert value which is no boolean, so it can be
+	// reported by Validate instead of failing to load the config.
+	invalidInvert string
+}
+
+// Reason returns the reason which is shown for resources matched by the
+// filter.
+func (f Filter) Reason() string {
+	if f.Description == "" {
+		return "filtered by config"
+	}
+	return fmt.Sprintf("filtered by config: %s", f.Description)
+}
+
+// IsGroup returns whether the filter combines other filters.
+func (f Filter) IsGroup() bool {
+	return f.All != nil || f.Any != nil || f.Not != nil
+}
+
+func (f Filter) values() []string {
+	if len(f.Values) == 0 {
+		return []string{f.Value}
+	}
+	if f.Value == "" {
+		return f.Values
+	}
+	return append([]string{f.Value}, f.Values...)
 }
 
 func (f Filter) Match(o string) (bool, error) {
+	if f.Type == FilterTypeBetween {
+		// The values are the bounds instead of alternatives.
+		return f.matchBetween(o)
+	}
+
+	for _, value := range f.values() {
+		match, err := f.match(value, o)
+		if err != nil || match {
+			return match, err
+		}
+	}
+	return false, nil
+}
+
+func (f Filter) match(value string, o string) (bool, error) {
 	switch f.Type {
 	case FilterTypeEmpty:
 		fallthrough
 
 	case FilterTypeExact:
-		return f.Value == o, nil
+		return value == o, nil
 
 	case FilterTypeContains:
-		return strings.Contains(o, f.Value), nil
+		return strings.Contains(o, value), nil
 
 	case FilterTypeGlob:
-		return glob.Match(f.Value, o)
+		return glob.Match(value, o)
 
 	case FilterTypeRegex:
-		re, err := regexp.Compile(f.Value)
+		re, err := regexp.Compile(value)
 		if err != nil {
 			return false, err
 		}
 		return re.MatchString(o), nil
 
-	case FilterTypeDateOlderThan:
-		if o == "" {
-			return false, nil
-		}
-		duration, err := time.ParseDuration(f.Value)
-		if err != nil {
-			return false, err
+	case FilterTypeDateOlderThan, FilterTypeDateNewerThan, FilterTypeDateBefore, FilterTypeDateAfter:
+		return f.matchDate(value, o)
+
+	case FilterTypeGreater, FilterTypeGreaterOrEqual, FilterTypeLess, FilterTypeLessOrEqual:
+		return f.matchComparison(value, o)
+
+	case FilterTypeCIDR:
+		return matchCIDR(value, o)
+
+	case FilterTypeCEL:
+		return false, fmt.Errorf("cel filters are evaluated against all properties")
+
+	default:
+		return false, fmt.Errorf("unknown type %s", f.Type)
+	}
+}
+
+// Validate checks the filter and the filters of its groups without matching
+// anything, so mistakes are noticed before the run.
+func (f Filter) Validate() error {
+	err := f.validate()
+	if err != nil {
+		return err
+	}
+
+	for name, children := range map[string][]Filter{"all": f.All, "any": f.Any} {
+		for i, child := range children {
+			err := child.Validate()
+			if err != nil {
+				return fmt.Errorf("%s[%d]: %w", name, i, err)
+			}
 		}
-		fieldTime, err := parseDate(o)
+	}
+	if f.Not != nil {
+		err := f.Not.Validate()
 		if err != nil {
-			return false, err
+			return fmt.Errorf("not: %w", err)
 		}
-		fieldTimeWithOffset := fieldTime.Add(duration)
+	}
+
+	return nil
+}
 
-		return fieldTimeWithOffset.After(time.Now()), nil
+// validate checks the filter without the filters of its groups.
+func (f Filter) validate() error {
+	if f.invalidInvert != "" {
+		return fmt.Errorf("invalid value '%s' for invert, expected true or false", f.invalidInvert)
+	}
 
+	switch f.Unparsable {
+	case "", UnparsableDateError, UnparsableDateMatch, UnparsableDateMismatch:
 	default:
-		return false, fmt.Errorf("unknown type %s", f.Type)
+		return fmt.Errorf("invalid value '%s' for unparsable, expected error, match or mismatch", f.Unparsable)
+	}
+	if f.Unparsable != "" && !f.isDate() {
+		return fmt.Errorf("unparsable only applies to date filters")
 	}
-}
 
-func parseDate(input string) (time.Time, error) {
-	if i, err := strconv.ParseInt(input, 10, 64); err == nil {
-		t := time.Unix(i, 0)
-		return t, nil
+	if f.Type == FilterTypeBetween {
+		return f.validateBetween()
 	}
 
-	formats := []string{"2006-01-02",
-		"2006/01/02",
-		"2006-01-02T15:04:05Z",
-		"2006-01-02 15:04:05.000 -0700 MST", // Date format used by AWS for CreateTime on ASGs
-		time.RFC3339Nano,                    // Format of t.MarshalText() and t.MarshalJSON()
-		time.RFC3339,
+	if !f.IsGroup() {
+		for _, value := range f.values() {
+			err := f.validateValue(value)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
 	}
-	for _, f := range formats {
-		t, err := time.Parse(f, input)
-		if err == nil {
-			return t, nil
+
+	groups := 0
+	for _, set := range []bool{f.All != nil, f.Any != nil, f.Not != nil} {
+		if set {
+			groups = groups + 1
 		}
 	}
-	return time.Now(), fmt.Errorf("unable to parse time %s", input)
+	switch {
+	case groups > 1:
+		return fmt.Errorf("a filter group must use only one of all, any and not")
+	case f.Property != "" || f.Type != FilterTypeEmpty || f.Value != "" || f.Values != nil:
+		return fmt.Errorf("a filter group must not have a property, type or value")
+	case f.Not == nil && len(f.All)+len(f.Any) == 0:
+		return fmt.Errorf("a filter group must not be empty")
+	}
+
+	return nil
+}
+
+func (f Filter) validateValue(value string) error {
+	switch f.Type {
+	case FilterTypeEmpty, FilterTypeExact, FilterTypeContains:
+
+	case FilterTypeGlob:
+		if _, err := glob.Match(value, ""); err != nil {
+			return fmt.Errorf("invalid glob '%s': %v", value, err)
+		}
+
+	case FilterTypeRegex:
+		if _, err := regexp.Compile(value); err != nil {
+			return fmt.Errorf("invalid regex '%s': %v", value, err)
+		}
+
+	case FilterTypeDateOlderThan, FilterTypeDateNewerThan:
+		if _, err := parseDuration(value); err != nil {
+			return fmt.Errorf("invalid duration '%s': %v", value, err)
+		}
+
+	case FilterTypeDateBefore, FilterTypeDateAfter:
+		if _, err := parseDate(value); err != nil {
+			return fmt.Errorf("invalid date '%s': %v", value, err)
+		}
+
+	case FilterTypeGreater, FilterTypeGreaterOrEqual, FilterTypeLess, FilterTypeLessOrEqual:
+		if !isComparable(value) {
+			return fmt.Errorf("invalid value '%s', expected a number or a version", value)
+		}
+
+	case FilterTypeCIDR:
+		if _, err := parsePrefix(value); err != nil {
+			return err
+		}
+
+	case FilterTypeCEL:
+		if _, err := cachedCEL(value); err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("unknown type %s", f.Type)
+	}
+
+	return nil
 }
 
 func (f *Filter) UnmarshalYAML(unmarshal func(interface{}) error) error {
@@ -109,19 +333,129 @@ func (f *Filter) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		return nil
 	}
 
-	m := map[string]string{}
-	err := unmarshal(m)
+	m := map[string]filterField{}
+	err := unmarshal(&m)
 	if err != nil {
 		return err
 	}
 
-	f.Type = FilterType(m["type"])
-	f.Value = m["value"]
-	f.Property = m["property"]
-	f.Invert = m["invert"]
+	for _, key := range []string{"property", "type", "value", "invert", "unparsable", "description"} {
+		if m[key].kind != filterFieldText {
+			return fmt.Errorf("the %s of a filter must not be a list or a map", key)
+		}
+	}
+	if values, ok := m["values"]; ok && values.kind != filterFieldItems {
+		return fmt.Errorf("the values of a filter must be a list")
+	}
+
+	f.Type = FilterType(m["type"].text)
+	f.Value = m["value"].text
+	f.Values = m["values"].items
+	f.Property = m["property"].text
+	f.Unparsable = UnparsableDate(m["unparsable"].text)
+	f.Description = m["description"].text
+
+	for _, group := range []struct {
+		key     string
+		filters *[]Filter
+	}{{"all", &f.All}, {"any", &f.Any}} {
+		field, ok := m[group.key]
+		if !ok {
+			continue
+		}
+		if field.kind != filterFieldItems && field.kind != filterFieldFilters {
+			return fmt.Errorf("%s of a filter must be a list of filters", group.key)
+		}
+		*group.filters = field.groupFilters()
+	}
+
+	if field, ok := m["not"]; ok {
+		switch field.kind {
+		case filterFieldFilter:
+			f.Not = field.filter
+		case filterFieldText:
+			not := NewExactFilter(field.text)
+			f.Not = &not
+		default:
+			return fmt.Errorf("not of a filter must be a single filter")
+		}
+	}
+
+	switch strings.TrimSpace(strings.ToLower(m["invert"].text)) {
+	case "", "false":
+	case "true":
+		f.Invert = true
+	default:
+		f.invalidInvert = m["invert"].text
+	}
+
 	return nil
 }
 
+type filterFieldKind int
+
+const (
+	filterFieldText filterFieldKind = iota
+	filterFieldItems
+	filterFieldFilters
+	filterFieldFilter
+)
+
+// filterField is a value of a filter definition. Scalars are kept as
+// written, since eg a version like 1.10 must not be read as number. Groups
+// contain further filters.
+type filterField struct {
+	kind    filterFieldKind
+	text    string
+	items   []string
+	filters []Filter
+	filter  *Filter
+}
+
+func (f *filterField) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var text string
+	if unmarshal(&text) == nil {
+		*f = filterField{kind: filterFieldText, text: text}
+		return nil
+	}
+
+	var items []string
+	if unmarshal(&items) == nil {
+		*f = filterField{kind: filterFieldItems, items: items}
+		return nil
+	}
+
+	var filters []Filter
+	err := unmarshal(&filters)
+	if err == nil {
+		*f = filterField{kind: filterFieldFilters, filters: filters}
+		return nil
+	}
+
+	filter := new(Filter)
+	if unmarshal(filter) == nil {
+		*f = filterField{kind: filterFieldFilter, filter: filter}
+		return nil
+	}
+
+	// The error of the list is the most helpful one for invalid groups.
+	return err
+}
+
+// groupFilters returns the filters of a group. Plain strings are exact
+// filters, like in the filter lists of resource types.
+func (f filterField) groupFilters() []Filter {
+	if f.kind == filterFieldFilters {
+		return f.filters
+	}
+
+	filters := []Filter{}
+	for _, item := range f.items {
+		filters = append(filters, NewExactFilter(item))
+	}
+	return filters
+}
+
 func NewExactFilter(value string) Filter {
 	return Filter{
 		Type:  FilterTypeExact,
@@ -1,6 +1,7 @@
 package config_test
 
 import (
+	"reflect"
 	"strconv"
 	"testing"
 	"time"
@@ -12,6 +13,7 @@ import (
 func TestUnmarshalFilter(t *testing.T) {
 	past := time.Now().UTC().Add(-24 * time.Hour)
 	future := time.Now().UTC().Add(24 * time.Hour)
+	now := time.Now()
 	cases := []struct {
 		yaml            string
 		match, mismatch []string
@@ -41,6 +43,16 @@ func TestUnmarshalFilter(t *testing.T) {
 			match:    []string{"bish", "bash", "bosh"},
 			mismatch: []string{"woooosh", "fooo", "o", "fo", "boooooosh", "bsh", "bush"},
 		},
+		{
+			yaml:     `{"values":["foo","bar"]}`,
+			match:    []string{"foo", "bar"},
+			mismatch: []string{"", "baz", "foobar"},
+		},
+		{
+			yaml:     `{"type":"glob","value":"a*","values":["b*"]}`,
+			match:    []string{"ab", "bc"},
+			mismatch: []string{"ca"},
+		},
 		{
 			yaml:     `{"type":"contains","value":"mba"}`,
 			match:    []string{"bimbaz", "mba", "bi mba z"},
@@ -64,6 +76,127 @@ func TestUnmarshalFilter(t *testing.T) {
 				past.Format(time.RFC3339),
 			},
 		},
+		{
+			yaml: `{"type":"dateOlderThan","value":"2d"}`,
+			match: []string{
+				now.Add(-36 * time.Hour).Format(time.RFC3339),
+			},
+			mismatch: []string{"",
+				now.Add(-60 * time.Hour).Format(time.RFC3339),
+			},
+		},
+		{
+			yaml: `{"type":"dateNewerThan","value":"1w"}`,
+			match: []string{
+				now.Add(-8 * 24 * time.Hour).Format(time.RFC3339Nano),
+				now.Add(-8 * 24 * time.Hour).String(),
+			},
+			mismatch: []string{"",
+				now.Add(-6 * 24 * time.Hour).Format(time.RFC3339Nano),
+				now.Add(-6 * 24 * time.Hour).String(),
+			},
+		},
+		{
+			yaml: `{"type":"dateNewerThan","value":"1w2d12h"}`,
+			match: []string{
+				now.Add(-10 * 24 * time.Hour).Format(time.RFC3339),
+			},
+			mismatch: []string{
+				now.Add(-9 * 24 * time.Hour).Format(time.RFC3339),
+			},
+		},
+		{
+			yaml: `{"type":"dateBefore","value":"2023-01-02T03:04:05-08:00"}`,
+			match: []string{
+				"2023-01-02T03:04:05.678-08:00",
+				"2023-01-02T11:04:05Z",
+				"1672657445",
+				"Mon, 02 Jan 2023 11:04:06 +0000",
+			},
+			mismatch: []string{"",
+				"2023-01-02T03:04:05.678-07:00",
+				"2023-01-02T11:04:04Z",
+				"seconds:1672657444 nanos:999999999",
+				"2023-01-02 11:04:04.999999999 +0000 UTC m=+0.000000001",
+			},
+		},
+		{
+			yaml: `{"type":"dateAfter","value":"2023-01-02"}`,
+			match: []string{
+				"2023-01-01T23:59:59Z",
+				"2023-01-02T00:30:00+01:00",
+			},
+			mismatch: []string{"",
+				"2023-01-02T00:00:01Z",
+				"2023/01/03",
+				"2023-01-02 00:00:01",
+			},
+		},
+		{
+			yaml:     `{"type":"dateAfter","value":"2023-01-02","unparsable":"match"}`,
+			match:    []string{"not a date", "2023-01-01"},
+			mismatch: []string{"", "2023-01-03"},
+		},
+		{
+			yaml:     `{"type":"dateBefore","value":"2023-01-02","unparsable":"mismatch"}`,
+			match:    []string{"2023-01-03"},
+			mismatch: []string{"not a date", "2023-01-01"},
+		},
+		{
+			yaml:     `{"type":"gt","value":"100"}`,
+			match:    []string{"101", "100.5", "1e3"},
+			mismatch: []string{"", "100", "99", "-200"},
+		},
+		{
+			yaml:     `{"type":"gte","value":"1.27"}`,
+			match:    []string{"1.27.0", "v1.27.3", "1.28.1-gke.100", "2.0.0"},
+			mismatch: []string{"1.26.9", "1.27.0-rc.1"},
+		},
+		{
+			yaml:     `{"type":"lt","value":"10"}`,
+			match:    []string{"9", "-1", "9.99"},
+			mismatch: []string{"10", "11"},
+		},
+		{
+			yaml:     `{"type":"lte","value":"v2.1.0"}`,
+			match:    []string{"2.1.0", "1.9.12", "v2.1.0-beta"},
+			mismatch: []string{"2.1.1", "v10.0.0"},
+		},
+		{
+			yaml:     `{"type":"gt","value":"1.9"}`,
+			match:    []string{"1.10", "1.9.1", "2"},
+			mismatch: []string{"1.9", "1.8.10"},
+		},
+		{
+			yaml:     `{"type":"lt","value":"1.10"}`,
+			match:    []string{"1.9", "1.2.3"},
+			mismatch: []string{"1.10", "1.11"},
+		},
+		{
+			yaml:     `{"type":"between","values":[10,20]}`,
+			match:    []string{"10", "15", "20"},
+			mismatch: []string{"", "9", "21"},
+		},
+		{
+			yaml:     `{"type":"between","values":["1.26","1.27.5"]}`,
+			match:    []string{"1.26.0", "1.27.3-gke.100", "1.27.5"},
+			mismatch: []string{"1.25.16", "1.28.0"},
+		},
+		{
+			yaml:     `{"type":"cidr","value":"10.0.0.0/8"}`,
+			match:    []string{"10.1.2.3", "10.128.0.0/20", "192.168.0.0/16,10.0.0.0/24"},
+			mismatch: []string{"", "11.0.0.1", "0.0.0.0/0", "10.0.0.0/7", "fd00::1"},
+		},
+		{
+			yaml:     `{"type":"cidr","values":["35.235.240.0/20","fd20::/20"]}`,
+			match:    []string{"35.235.240.7", "fd20:a:b::/48"},
+			mismatch: []string{"35.235.224.0/20", "fd00::1"},
+		},
+		{
+			yaml:     `{"type":"cidr","value":"10.1.2.3"}`,
+			match:    []string{"10.1.2.3", "10.1.2.3/32"},
+			mismatch: []string{"10.1.2.4", "10.1.2.0/24"},
+		},
 	}
 
 	for _, tc := range cases {
@@ -100,3 +233,165 @@ func TestUnmarshalFilter(t *testing.T) {
 	}
 
 }
+
+func TestUnmarshalFilterFields(t *testing.T) {
+	cases := []struct {
+		yaml   string
+		want   config.Filter
+		reason string
+	}{
+		{
+			yaml:   `{"property":"Name","value":"foo","invert":true}`,
+			want:   config.Filter{Property: "Name", Value: "foo", Invert: true},
+			reason: "filtered by config",
+		},
+		{
+			yaml:   `{"property":"Name","value":"foo","invert":"True"}`,
+			want:   config.Filter{Property: "Name", Value: "foo", Invert: true},
+			reason: "filtered by config",
+		},
+		{
+			yaml: `{"property":"Version","values":[1.10, "2.0", true],"description":"pinned versions"}`,
+			want: config.Filter{
+				Property:    "Version",
+				Values:      []string{"1.10", "2.0", "true"},
+				Description: "pinned versions",
+			},
+			reason: "filtered by config: pinned versions",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.yaml, func(t *testing.T) {
+			var filter config.Filter
+
+			err := yaml.Unmarshal([]byte(tc.yaml), &filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(filter, tc.want) {
+				t.Errorf("Wrong filter. Want: %+v. Have: %+v", tc.want, filter)
+			}
+			if filter.Reason() != tc.reason {
+				t.Errorf("Wrong reason. Want: %s. Have: %s", tc.reason, filter.Reason())
+			}
+		})
+	}
+}
+
+func TestUnmarshalFilterErrors(t *testing.T) {
+	for _, input := range []string{
+		`{"value":["foo"]}`,
+		`{"values":"foo"}`,
+		`{"invert":[true]}`,
+	} {
+		var filter config.Filter
+
+		err := yaml.Unmarshal([]byte(input), &filter)
+		if err == nil {
+			t.Errorf("Expected an error for %s but didn't get one.", input)
+		}
+	}
+
+	var filter config.Filter
+	err := yaml.Unmarshal([]byte(`{"value":"foo","invert":"yes please"}`), &filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.Invert {
+		t.Errorf("An invalid invert must not invert the filter.")
+	}
+	if filter.Validate() == nil {
+		t.Errorf("Expected a validation error for an invalid invert.")
+	}
+}
+
+func TestDateFilterErrors(t *testing.T) {
+	cases := []config.Filter{
+		{Type: config.FilterTypeDateNewerThan, Value: "7 days"},
+		{Type: config.FilterTypeDateBefore, Value: "yesterday"},
+		{Type: config.FilterTypeDateAfter, Value: "2023-01-02", Unparsable: "ignore"},
+		{Type: config.FilterTypeExact, Value: "foo", Unparsable: config.UnparsableDateMatch},
+	}
+
+	for _, filter := range cases {
+		if filter.Validate() == nil {
+			t.Errorf("Expected a validation error for %+v but didn't get one.", filter)
+		}
+	}
+
+	// Unparsable dates are an error by default, which stops the run.
+	filter := config.Filter{Type: config.FilterTypeDateAfter, Value: "2023-01-02"}
+	_, err := filter.Match("not a date")
+	if err == nil {
+		t.Errorf("Expected an error for an unparsable date but didn't get one.")
+	}
+}
+
+func TestComparisonFilterErrors(t *testing.T) {
+	cases := []config.Filter{
+		{Type: config.FilterTypeGreater, Value: "large"},
+		{Type: config.FilterTypeLessOrEqual, Value: "1.2.3.4"},
+		{Type: config.FilterTypeGreater, Value: "NaN"},
+		{Type: config.FilterTypeLess, Value: "Inf"},
+		{Type: config.FilterTypeGreaterOrEqual, Value: "-Infinity"},
+		{Type: config.FilterTypeLess, Value: "0x1p4"},
+		{Type: config.FilterTypeBetween, Value: "10"},
+		{Type: config.FilterTypeBetween, Values: []string{"1", "2", "3"}},
+		{Type: config.FilterTypeBetween, Values: []string{"20", "10"}},
+		{Type: config.FilterTypeBetween, Values: []string{"1", "many"}},
+		{Type: config.FilterTypeCIDR, Value: "10.0.0.0/33"},
+		{Type: config.FilterTypeCIDR, Value: "default"},
+	}
+
+	for _, filter := range cases {
+		if filter.Validate() == nil {
+			t.Errorf("Expected a validation error for %+v but didn't get one.", filter)
+		}
+	}
+
+	matches := []struct {
+		filter config.Filter
+		value  string
+	}{
+		{config.Filter{Type: config.FilterTypeGreater, Value: "10"}, "ten"},
+		{config.Filter{Type: config.FilterTypeGreater, Value: "10"}, "NaN"},
+		{config.Filter{Type: config.FilterTypeCIDR, Value: "10.0.0.0/8"}, "default,10.0.0.1"},
+	}
+	for _, tc := range matches {
+		_, err := tc.filter.Match(tc.value)
+		if err == nil {
+			t.Errorf("Expected an error for %+v with '%s' but didn't get one.", tc.filter, tc.value)
+		}
+	}
+}
+
+func TestRelativeDateFilters(t *testing.T) {
+	now := time.Now()
+	dates := []string{
+		now.Add(-1 * time.Hour).Format(time.RFC3339),
+		now.Add(-3 * 24 * time.Hour).Format(time.RFC3339),
+		now.Add(-30 * 24 * time.Hour).Format(time.RFC3339),
+		now.Add(-400 * 24 * time.Hour).Format(time.RFC3339),
+	}
+
+	older := config.Filter{Type: config.FilterTypeDateOlderThan, Value: "7d"}
+	newer := config.Filter{Type: config.FilterTypeDateNewerThan, Value: "7d"}
+
+	// Every date is matched by exactly one of the types, so either the old
+	// or the new resources are nuked.
+	for _, date := range dates {
+		matchOlder, err := older.Match(date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		matchNewer, err := newer.Match(date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if matchOlder == matchNewer {
+			t.Errorf("Both types return %t for '%s'.", matchOlder, date)
+		}
+	}
+}
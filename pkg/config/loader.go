@@ -0,0 +1,214 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A Fetcher reads a remote config file, like gs://bucket/nuke.yaml or an
+// https URL. It is provided by the caller, since fetching needs the
+// credentials of the run.
+type Fetcher func(location string) ([]byte, error)
+
+// Loader reads config files from disk or remote locations. Every file is
+// only read once, so all steps of loading see the same content.
+type Loader struct {
+	Variables Variables
+
+	// Fetch reads remote config files. Without it remote configs are
+	// refused.
+	Fetch Fetcher
+
+	// Checksums pins the expected SHA-256 of config files by their path, as
+	// hex string.
+	Checksums map[string]string
+
+	contents map[string][]byte
+}
+
+// IsRemote returns whether the path of a config file refers to a remote
+// location instead of a local file.
+func IsRemote(path string) bool {
+	return strings.HasPrefix(path, "gs://") || strings.HasPrefix(path, "https://") ||
+		strings.HasPrefix(path, "http://")
+}
+
+// ReadFile returns the content of a config file and checks its checksum, if
+// it is pinned.
+func (l *Loader) ReadFile(path string) ([]byte, error) {
+	raw, ok := l.contents[path]
+	if ok {
+		return raw, nil
+	}
+
+	raw, err := l.read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = l.verify(path, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.contents == nil {
+		l.contents = map[string][]byte{}
+	}
+	l.contents[path] = raw
+	return raw, nil
+}
+
+func (l *Loader) verify(path string, raw []byte) error {
+	want, ok := l.checksum(path)
+	if !ok {
+		return nil
+	}
+
+	sum := sha256.Sum256(raw)
+	have := hex.EncodeToString(sum[:])
+	if !strings.EqualFold(have, strings.TrimSpace(want)) {
+		return fmt.Errorf("The config file %s has the SHA-256 %s, but %s is expected. "+
+			"Refusing to use it.", path, have, want)
+	}
+	return nil
+}
+
+// pin adds the checksum of an included file. A file which was already read
+// is verified right away.
+func (l *Loader) pin(path string, checksum string) error {
+	if !IsSHA256(checksum) {
+		return fmt.Errorf("The sha256 '%s' of the include '%s' is no hex encoded SHA-256.", checksum, path)
+	}
+
+	if pinned, ok := l.checksum(path); ok {
+		if !strings.EqualFold(strings.TrimSpace(pinned), strings.TrimSpace(checksum)) {
+			return fmt.Errorf("The config file %s is pinned with different checksums.", path)
+		}
+		return nil
+	}
+
+	if l.Checksums == nil {
+		l.Checksums = map[string]string{}
+	}
+	l.Checksums[path] = checksum
+
+	if raw, ok := l.contents[path]; ok {
+		return l.verify(path, raw)
+	}
+	return nil
+}
+
+// IsSHA256 returns whether the value is a hex encoded SHA-256.
+func IsSHA256(value string) bool {
+	decoded, err := hex.DecodeString(strings.TrimSpace(value))
+	return err == nil && len(decoded) == sha256.Size
+}
+
+func (l *Loader) checksum(path string) (string, bool) {
+	for pinned, checksum := range l.Checksums {
+		if clean(pinned) == path {
+			return checksum, true
+		}
+	}
+	return "", false
+}
+
+func (l *Loader) read(path string) ([]byte, error) {
+	if !IsRemote(path) {
+		return os.ReadFile(path)
+	}
+
+	if strings.HasPrefix(path, "http://") {
+		return nil, fmt.Errorf("The config file %s has to be fetched with https.", path)
+	}
+	if l.Fetch == nil {
+		return nil, fmt.Errorf("The config file %s is remote, which is not supported here.", path)
+	}
+
+	raw, err := l.Fetch(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch the config file %s: %w", path, err)
+	}
+	return raw, nil
+}
+
+// clean normalizes the path of a config file, so each file is only used
+// once.
+func clean(path string) string {
+	if IsRemote(path) {
+		return path
+	}
+	return filepath.Clean(path)
+}
+
+// An Include is a config file included by another one. Besides the path, it
+// can pin the expected SHA-256 of the file, which is required for remote
+// includes of pinned files.
+type Include struct {
+	Path   string `yaml:"path"`
+	SHA256 string `yaml:"sha256"`
+}
+
+// UnmarshalYAML accepts a plain path or a path with checksum.
+func (i *Include) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var path string
+	if err := unmarshal(&path); err == nil {
+		*i = Include{Path: path}
+		return nil
+	}
+
+	type include Include
+	var pinned include
+	if err := unmarshal(&pinned); err != nil {
+		return err
+	}
+	*i = Include(pinned)
+	return nil
+}
+
+// resolveInclude returns the paths of an include relative to the including
+// file. Globs are only expanded for local files, since remote locations
+// cannot be listed in general.
+func resolveInclude(from string, include string) ([]string, error) {
+	if IsRemote(include) {
+		if hasGlobMeta(include) {
+			return nil, fmt.Errorf("The remote include '%s' must not contain globs.", include)
+		}
+		return []string{include}, nil
+	}
+
+	if IsRemote(from) {
+		if hasGlobMeta(include) {
+			return nil, fmt.Errorf("The include '%s' of a remote config file must not contain globs.", include)
+		}
+
+		base, err := url.Parse(from)
+		if err != nil {
+			return nil, err
+		}
+		ref, err := url.Parse(include)
+		if err != nil {
+			return nil, err
+		}
+		return []string{base.ResolveReference(ref).String()}, nil
+	}
+
+	pattern := include
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(filepath.Dir(from), pattern)
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include '%s': %w", include, err)
+	}
+	if len(matches) == 0 && !hasGlobMeta(include) {
+		return nil, fmt.Errorf("The included file '%s' does not exist.", include)
+	}
+	return matches, nil
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const remoteWeb = `
+projects:
+  web-dev:
+    locations: [global]
+`
+
+var remoteConfigs = map[string]string{
+	"gs://configs/nuke/base.yaml": `
+include:
+- path: teams/web.yaml
+  sha256: ` + checksum(remoteWeb) + `
+project-restricted-list:
+- production-project
+`,
+	"gs://configs/nuke/teams/web.yaml": remoteWeb,
+	"gs://configs/nuke/unpinned.yaml": `
+include:
+- teams/web.yaml
+`,
+	"gs://configs/nuke/tampered.yaml": `
+include:
+- path: https://example.com/web.yaml
+  sha256: ` + checksum(remoteWeb) + `
+`,
+	"https://example.com/web.yaml": remoteWeb + "project-restricted-list: []\n",
+}
+
+func fakeFetch(location string) ([]byte, error) {
+	raw, ok := remoteConfigs[location]
+	if !ok {
+		return nil, fmt.Errorf("storage: object doesn't exist")
+	}
+	return []byte(raw), nil
+}
+
+func checksum(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestLoaderRemote(t *testing.T) {
+	loader := &Loader{
+		Fetch:     fakeFetch,
+		Checksums: map[string]string{"gs://configs/nuke/base.yaml": checksum(remoteConfigs["gs://configs/nuke/base.yaml"])},
+	}
+
+	files, err := loader.Files("gs://configs/nuke/base.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFiles := []string{"gs://configs/nuke/teams/web.yaml", "gs://configs/nuke/base.yaml"}
+	if !reflect.DeepEqual(files, wantFiles) {
+		t.Errorf("Wrong files. Want: %v. Have: %v", wantFiles, files)
+	}
+
+	config, err := loader.Load("gs://configs/nuke/base.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(config.ProjectRestrictedList, []string{"production-project"}) {
+		t.Errorf("Wrong restricted list. Want: [production-project]. Have: %v", config.ProjectRestrictedList)
+	}
+	if _, ok := config.Projects["web-dev"]; !ok {
+		t.Errorf("The project web-dev of the remote include is missing.")
+	}
+
+	// Unpinned files may include remote files without checksum.
+	_, err = (&Loader{Fetch: fakeFetch}).Load("gs://configs/nuke/unpinned.yaml")
+	if err != nil {
+		t.Errorf("Didn't expect an error, but got one: %v", err)
+	}
+}
+
+func TestLoaderErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		loader *Loader
+		path   string
+		want   string
+	}{
+		{
+			name:   "checksum mismatch",
+			loader: &Loader{Fetch: fakeFetch, Checksums: map[string]string{"gs://configs/nuke/base.yaml": checksum("other")}},
+			path:   "gs://configs/nuke/base.yaml",
+			want:   "but " + checksum("other") + " is expected. Refusing to use it.",
+		},
+		{
+			name:   "local checksum mismatch",
+			loader: &Loader{Checksums: map[string]string{"./test-fixtures/compose/teams/data.yaml": checksum("other")}},
+			path:   "./test-fixtures/compose/teams/data.yaml",
+			want:   "Refusing to use it.",
+		},
+		{
+			name:   "unpinned remote include",
+			loader: &Loader{Fetch: fakeFetch, Checksums: map[string]string{"gs://configs/nuke/unpinned.yaml": checksum(remoteConfigs["gs://configs/nuke/unpinned.yaml"])}},
+			path:   "gs://configs/nuke/unpinned.yaml",
+			want:   "The config file is pinned, so its remote include 'teams/web.yaml' has to be pinned with sha256, too.",
+		},
+		{
+			name:   "include checksum mismatch",
+			loader: &Loader{Fetch: fakeFetch},
+			path:   "gs://configs/nuke/tampered.yaml",
+			want:   "The config file https://example.com/web.yaml has the SHA-256",
+		},
+		{
+			name:   "fetch failure",
+			loader: &Loader{Fetch: fakeFetch},
+			path:   "gs://configs/nuke/none.yaml",
+			want:   "Failed to fetch the config file gs://configs/nuke/none.yaml: storage: object doesn't exist",
+		},
+		{
+			name:   "no fetcher",
+			loader: &Loader{},
+			path:   "https://example.com/nuke.yaml",
+			want:   "The config file https://example.com/nuke.yaml is remote, which is not supported here.",
+		},
+		{
+			name:   "plain http",
+			loader: &Loader{Fetch: fakeFetch},
+			path:   "http://example.com/nuke.yaml",
+			want:   "The config file http://example.com/nuke.yaml has to be fetched with https.",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.loader.Load(tc.path)
+			if err == nil {
+				t.Fatal("Expected an error but didn't get one.")
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Wrong error. Want: %s. Have: %v", tc.want, err)
+			}
+		})
+	}
+}
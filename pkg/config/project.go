@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mb0/glob"
+)
+
+// ProjectPattern is a key of the projects map or an entry of the
+// project-restricted-list. Project IDs consist only of lowercase letters,
+// digits and hyphens, so patterns are recognized by their syntax: Entries
+// wrapped in slashes are regular expressions, entries containing '*', '?' or
+// '[' are globs and all others are exact project IDs.
+type ProjectPattern string
+
+type ProjectPatternKind int
+
+// The kinds are ordered by their precedence, if several projects match.
+const (
+	ProjectPatternExact ProjectPatternKind = iota
+	ProjectPatternGlob
+	ProjectPatternRegex
+)
+
+func (p ProjectPattern) Kind() ProjectPatternKind {
+	s := string(p)
+	switch {
+	case len(s) >= 2 && strings.HasPrefix(s, "/") && strings.HasSuffix(s, "/"):
+		return ProjectPatternRegex
+	case strings.ContainsAny(s, "*?["):
+		return ProjectPatternGlob
+	default:
+		return ProjectPatternExact
+	}
+}
+
+// regexp returns the compiled regular expression, which has to match the
+// whole project ID.
+func (p ProjectPattern) regexp() (*regexp.Regexp, error) {
+	s := string(p)
+	return regexp.Compile("^(?:" + s[1:len(s)-1] + ")$")
+}
+
+func (p ProjectPattern) Validate() error {
+	switch p.Kind() {
+	case ProjectPatternGlob:
+		if _, err := glob.Match(string(p), ""); err != nil {
+			return fmt.Errorf("invalid glob '%s': %v", p, err)
+		}
+	case ProjectPatternRegex:
+		if _, err := p.regexp(); err != nil {
+			return fmt.Errorf("invalid regex '%s': %v", p, err)
+		}
+	}
+	return nil
+}
+
+func (p ProjectPattern) Match(projectID string) (bool, error) {
+	switch p.Kind() {
+	case ProjectPatternGlob:
+		return glob.Match(string(p), projectID)
+	case ProjectPatternRegex:
+		re, err := p.regexp()
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(projectID), nil
+	default:
+		return string(p) == projectID, nil
+	}
+}
+
+// precedes decides which of two matching projects is used: Exact IDs win
+// over globs and globs win over regular expressions. Between patterns of the
+// same kind the longer, and thus usually more specific, one wins. The
+// lexical order is the last resort, so the result never depends on the
+// order of the map.
+func (p ProjectPattern) precedes(other ProjectPattern) bool {
+	if p.Kind() != other.Kind() {
+		return p.Kind() < other.Kind()
+	}
+	if len(p) != len(other) {
+		return len(p) > len(other)
+	}
+	return p < other
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestProjectPatternKind(t *testing.T) {
+	cases := []struct {
+		pattern ProjectPattern
+		want    ProjectPatternKind
+	}{
+		{"my-project", ProjectPatternExact},
+		{"ci-pr-*", ProjectPatternGlob},
+		{"ci-pr-????", ProjectPatternGlob},
+		{"ci-[ab]", ProjectPatternGlob},
+		{"/ci-pr-[0-9]+-.*/", ProjectPatternRegex},
+		{"/", ProjectPatternExact},
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.pattern), func(t *testing.T) {
+			have := tc.pattern.Kind()
+			if have != tc.want {
+				t.Errorf("Wrong kind. Want: %d. Have: %d", tc.want, have)
+			}
+		})
+	}
+}
+
+func TestMatchProject(t *testing.T) {
+	config := &Nuke{
+		Projects: map[string]Project{
+			"ci-pr-1234-abc":    {},
+			"ci-pr-*":           {},
+			"ci-*":              {},
+			"ci-pr-1*":          {},
+			"ci-pr-9*":          {},
+			"/ci-pr-[0-9]+-.*/": {},
+			"/.*-sandbox/":      {},
+			"/dev-.*/":          {},
+		},
+	}
+
+	cases := []struct {
+		project string
+		want    string
+	}{
+		{"ci-pr-1234-abc", "ci-pr-1234-abc"},
+		{"ci-pr-1235-abc", "ci-pr-1*"},
+		{"ci-pr-42-abc", "ci-pr-*"},
+		{"ci-main", "ci-*"},
+		{"team-sandbox", "/.*-sandbox/"},
+		{"dev-sandbox", "/.*-sandbox/"},
+		{"dev-test", "/dev-.*/"},
+		{"my-dev-test", ""},
+		{"production", ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.project, func(t *testing.T) {
+			have, err := config.MatchProject(tc.project)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have != tc.want {
+				t.Errorf("Wrong project. Want: %s. Have: %s", tc.want, have)
+			}
+		})
+	}
+}
+
+func TestMatchProjectInvalid(t *testing.T) {
+	config := &Nuke{
+		Projects: map[string]Project{
+			"/ci-(/": {},
+		},
+	}
+
+	_, err := config.MatchProject("ci-pr-1")
+	if err == nil {
+		t.Fatal("Expected an error for an invalid pattern.")
+	}
+}
+
+func TestValidateProjectPatterns(t *testing.T) {
+	cases := []struct {
+		name       string
+		restricted []string
+		project    string
+		shouldFail bool
+	}{
+		{"allowed", []string{"production"}, "ci-pr-1-abc", false},
+		{"restricted exact", []string{"ci-pr-1-abc"}, "ci-pr-1-abc", true},
+		{"restricted glob", []string{"ci-pr-1-*"}, "ci-pr-1-abc", true},
+		{"restricted regex", []string{"/.*-abc/"}, "ci-pr-1-abc", true},
+		{"unanchored regex", []string{"/pr-1/"}, "ci-pr-1-abc", false},
+		{"invalid regex", []string{"production", "/prod-(/"}, "ci-pr-1-abc", true},
+		{"not listed", []string{"production"}, "staging", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &Nuke{
+				ProjectRestrictedList: tc.restricted,
+				Projects: map[string]Project{
+					"/ci-pr-[0-9]+-.*/": {},
+				},
+			}
+
+			err := config.ValidateProject(tc.project)
+			if tc.shouldFail && err == nil {
+				t.Fatal("Expected an error but didn't get one.")
+			}
+			if !tc.shouldFail && err != nil {
+				t.Fatalf("Didn't expect an error, but got one: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/dshelley66/gcp-nuke/pkg/types"
+)
+
+// SchemaURL identifies the JSON Schema draft of the generated schema. Draft 7
+// is the newest one which is supported by most editors.
+const SchemaURL = "http://json-schema.org/draft-07/schema#"
+
+// JSONSchema is a JSON Schema document or a part of it.
+type JSONSchema map[string]interface{}
+
+// Schema generates a JSON Schema for config files from the structs of the
+// config. Resource types, their properties and APIs are taken from the
+// catalog, so editors can complete them.
+func Schema(catalog Catalog) JSONSchema {
+	g := schemaGenerator{
+		catalog: catalog,
+		defs:    JSONSchema{},
+	}
+
+	root := g.schema(reflect.TypeOf(Nuke{}))
+	root["$schema"] = SchemaURL
+	root["title"] = "gcp-nuke config"
+
+	properties := root["properties"].(JSONSchema)
+	properties["removal-parallelism"] = JSONSchema{
+		"type":                 "object",
+		"propertyNames":        JSONSchema{"enum": catalog.APIs},
+		"additionalProperties": JSONSchema{"type": "integer", "minimum": 1},
+	}
+	properties["retry-budgets"] = JSONSchema{
+		"type":                 "object",
+		"propertyNames":        JSONSchema{"enum": g.resourceTypes()},
+		"additionalProperties": JSONSchema{"type": "integer", "minimum": 0},
+	}
+	properties["presets"].(JSONSchema)["additionalProperties"] = g.schema(reflect.TypeOf(PresetDefinitions{}))
+
+	root["definitions"] = g.defs
+	return root
+}
+
+type schemaGenerator struct {
+	catalog Catalog
+	defs    JSONSchema
+}
+
+var (
+	filtersType    = reflect.TypeOf(Filters{})
+	flagsType      = reflect.TypeOf(DisableDeletionProtection{})
+	collectionType = reflect.TypeOf(types.Collection{})
+	includeType    = reflect.TypeOf(Include{})
+)
+
+func (g *schemaGenerator) schema(t reflect.Type) JSONSchema {
+	switch t {
+	case filtersType:
+		return g.ref("filters", g.filters)
+
+	case flagsType:
+		return JSONSchema{
+			"type":                 "object",
+			"propertyNames":        JSONSchema{"enum": g.resourceTypes()},
+			"additionalProperties": JSONSchema{"type": "boolean"},
+		}
+
+	case collectionType:
+		return JSONSchema{
+			"type":  "array",
+			"items": JSONSchema{"enum": g.resourceTypes()},
+		}
+
+	case includeType:
+		return JSONSchema{
+			"oneOf": []JSONSchema{
+				{"type": "string"},
+				{
+					"type": "object",
+					"properties": JSONSchema{
+						"path":   JSONSchema{"type": "string"},
+						"sha256": JSONSchema{"type": "string", "pattern": "^[0-9a-fA-F]{64}$"},
+					},
+					"required":             []string{"path"},
+					"additionalProperties": false,
+				},
+			},
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schema(t.Elem())
+
+	case reflect.Struct:
+		properties := JSONSchema{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+			if name == "-" || !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			properties[name] = g.schema(field.Type)
+		}
+		return JSONSchema{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+
+	case reflect.Map:
+		return JSONSchema{
+			"type":                 "object",
+			"additionalProperties": g.schema(t.Elem()),
+		}
+
+	case reflect.Slice, reflect.Array:
+		return JSONSchema{
+			"type":  "array",
+			"items": g.schema(t.Elem()),
+		}
+
+	case reflect.String:
+		return JSONSchema{"type": "string"}
+
+	case reflect.Bool:
+		return JSONSchema{"type": "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return JSONSchema{"type": "integer"}
+
+	case reflect.Float32, reflect.Float64:
+		return JSONSchema{"type": "number"}
+	}
+
+	panic(fmt.Sprintf("cannot generate a schema for %s", t))
+}
+
+// ref adds a definition once and refers to it, so repeated parts of the
+// config do not blow up the schema.
+func (g *schemaGenerator) ref(name string, build func() JSONSchema) JSONSchema {
+	if _, ok := g.defs[name]; !ok {
+		g.defs[name] = build()
+	}
+	return JSONSchema{"$ref": "#/definitions/" + name}
+}
+
+// resourceTypes returns the names of all resource types including their
+// aliases, which are still accepted.
+func (g *schemaGenerator) resourceTypes() []string {
+	names := []string{}
+	for name := range g.catalog.ResourceTypes {
+		names = append(names, name)
+	}
+	for alias := range g.catalog.Aliases {
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (g *schemaGenerator) filters() JSONSchema {
+	properties := JSONSchema{}
+	for _, name := range g.resourceTypes() {
+		resourceType := name
+		if current, ok := g.catalog.Aliases[name]; ok {
+			resourceType = current
+		}
+
+		properties[name] = JSONSchema{
+			"type":  "array",
+			"items": g.ref("filter."+resourceType, func() JSONSchema { return g.filter(resourceType) }),
+		}
+	}
+
+	return JSONSchema{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+func (g *schemaGenerator) filter(resourceType string) JSONSchema {
+	info := g.catalog.ResourceTypes[resourceType]
+
+	property := JSONSchema{"type": "string"}
+	if len(info.Properties) > 0 {
+		property = JSONSchema{
+			"anyOf": []JSONSchema{
+				{"enum": info.Properties},
+				{"type": "string", "pattern": "^tag:"},
+			},
+		}
+	}
+
+	filterTypes := []string{}
+	for _, t := range FilterTypes {
+		filterTypes = append(filterTypes, string(t))
+	}
+
+	// The values are read as strings, so YAML scalars of any type work.
+	scalar := JSONSchema{"type": []string{"string", "number", "boolean"}}
+
+	object := JSONSchema{
+		"type": "object",
+		"properties": JSONSchema{
+			"property": property,
+			"type":     JSONSchema{"enum": filterTypes},
+			"value":    scalar,
+			"values":   JSONSchema{"type": "array", "items": scalar},
+			"invert":   JSONSchema{"enum": []interface{}{true, false, "true", "false"}},
+			"unparsable": JSONSchema{"enum": []string{
+				string(UnparsableDateError), string(UnparsableDateMatch), string(UnparsableDateMismatch),
+			}},
+			"description": JSONSchema{"type": "string"},
+		},
+		"anyOf": []JSONSchema{
+			{"required": []string{"value"}},
+			{"required": []string{"values"}},
+		},
+		"additionalProperties": false,
+	}
+	if !info.LegacyID {
+		// Expressions refer to all properties, other filters need one.
+		object["allOf"] = []JSONSchema{{
+			"anyOf": []JSONSchema{
+				{"required": []string{"property"}},
+				{
+					"required":   []string{"type"},
+					"properties": JSONSchema{"type": JSONSchema{"const": string(FilterTypeCEL)}},
+				},
+			},
+		}}
+	}
+
+	// Groups combine filters of the same resource type.
+	self := JSONSchema{"$ref": "#/definitions/filter." + resourceType}
+	group := JSONSchema{
+		"type": "object",
+		"properties": JSONSchema{
+			"all":         JSONSchema{"type": "array", "items": self, "minItems": 1},
+			"any":         JSONSchema{"type": "array", "items": self, "minItems": 1},
+			"not":         self,
+			"invert":      JSONSchema{"enum": []interface{}{true, false, "true", "false"}},
+			"description": JSONSchema{"type": "string"},
+		},
+		"oneOf": []JSONSchema{
+			{"required": []string{"all"}},
+			{"required": []string{"any"}},
+			{"required": []string{"not"}},
+		},
+		"additionalProperties": false,
+	}
+
+	filter := JSONSchema{"anyOf": []JSONSchema{object, group}}
+	if info.LegacyID {
+		// Plain values are matched exactly against the legacy ID.
+		filter["anyOf"] = []JSONSchema{scalar, object, group}
+	}
+	return filter
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	catalog := Catalog{
+		ResourceTypes: map[string]ResourceTypeInfo{
+			"Bucket":          {Properties: []string{"CreationTime", "Name"}},
+			"ComputeInstance": {Properties: []string{"Name"}, Preparer: true},
+			"Legacy":          {LegacyID: true},
+		},
+		APIs:    []string{"compute", "storage"},
+		Aliases: map[string]string{"StorageBucket": "Bucket"},
+	}
+
+	schema := Schema(catalog)
+
+	// The schema has to survive a round trip through JSON, which is how it
+	// gets consumed.
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	err = json.Unmarshal(data, &doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	properties := doc["properties"].(map[string]interface{})
+	for _, key := range []string{"include", "project-restricted-list", "projects", "filters",
+		"resource-types", "presets", "feature-flags", "removal-parallelism", "retry-budgets", "endpoints"} {
+		if _, ok := properties[key]; !ok {
+			t.Errorf("The key %s is missing in the schema.", key)
+		}
+	}
+
+	definitions := doc["definitions"].(map[string]interface{})
+
+	filters := definitions["filters"].(map[string]interface{})["properties"].(map[string]interface{})
+	have := []string{}
+	for name := range filters {
+		have = append(have, name)
+	}
+	want := []string{"Bucket", "ComputeInstance", "Legacy", "StorageBucket"}
+	sort.Strings(have)
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("Wrong filter keys. Want: %v. Have: %v", want, have)
+	}
+
+	alias := filters["StorageBucket"].(map[string]interface{})["items"].(map[string]interface{})
+	if alias["$ref"] != "#/definitions/filter.Bucket" {
+		t.Errorf("Wrong filter of the alias. Want: #/definitions/filter.Bucket. Have: %v", alias["$ref"])
+	}
+
+	bucket := definitions["filter.Bucket"].(map[string]interface{})["anyOf"].([]interface{})
+	if len(bucket) != 2 {
+		t.Fatalf("Wrong number of filter forms. Want: 2. Have: %d", len(bucket))
+	}
+	object := bucket[0].(map[string]interface{})["properties"].(map[string]interface{})
+	property := object["property"].(map[string]interface{})["anyOf"].([]interface{})[0].(map[string]interface{})
+	if !reflect.DeepEqual(property["enum"], []interface{}{"CreationTime", "Name"}) {
+		t.Errorf("Wrong properties. Want: [CreationTime Name]. Have: %v", property["enum"])
+	}
+	if len(object["type"].(map[string]interface{})["enum"].([]interface{})) != len(FilterTypes) {
+		t.Errorf("Wrong filter types. Want: %v. Have: %v", FilterTypes, object["type"])
+	}
+
+	legacy := definitions["filter.Legacy"].(map[string]interface{})["anyOf"].([]interface{})
+	if len(legacy) != 3 {
+		t.Errorf("Wrong number of filter forms. Want: 3. Have: %d", len(legacy))
+	}
+
+	group := bucket[1].(map[string]interface{})["properties"].(map[string]interface{})
+	not := group["not"].(map[string]interface{})
+	if not["$ref"] != "#/definitions/filter.Bucket" {
+		t.Errorf("Wrong filter of the group. Want: #/definitions/filter.Bucket. Have: %v", not["$ref"])
+	}
+}
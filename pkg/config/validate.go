@@ -0,0 +1,439 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
+	"gopkg.in/yaml.v3"
+)
+
+// Catalog describes the resource types and APIs known to gcp-nuke. It has to
+// be passed in, since the resources package depends on this package.
+type Catalog struct {
+	ResourceTypes map[string]ResourceTypeInfo
+	APIs          []string
+
+	// Aliases maps former names of resource types to their current names.
+	Aliases map[string]string
+}
+
+// ResourceTypeInfo describes what the filters of a resource type can refer
+// to.
+type ResourceTypeInfo struct {
+	Properties []string
+	LegacyID   bool
+
+	// Preparer is set for types which can be prepared for their removal with
+	// the disable-deletion-protection feature flag.
+	Preparer bool
+}
+
+func (i ResourceTypeInfo) HasProperty(property string) bool {
+	// Tags are emitted for the labels of each resource and cannot be known
+	// in advance.
+	if strings.HasPrefix(property, "tag:") {
+		return true
+	}
+
+	for _, p := range i.Properties {
+		if p == property {
+			return true
+		}
+	}
+	return false
+}
+
+// A Problem is a semantic error in a config file. Problems which concern
+// the merged config as a whole have no file. Warnings, like the use of
+// deprecated names, do not make the config invalid.
+type Problem struct {
+	File    string
+	Line    int
+	Path    string
+	Message string
+	Warning bool
+}
+
+func (p Problem) String() string {
+	message := p.Message
+	if p.Warning {
+		message = "warning: " + message
+	}
+
+	if p.File == "" {
+		return fmt.Sprintf("%s: %s", p.Path, message)
+	}
+	return fmt.Sprintf("%s:%d: %s: %s", p.File, p.Line, p.Path, message)
+}
+
+// isLocation checks for 'all', 'global', the multi-regions and dual-regions
+// of Cloud Storage and BigQuery, regions and zones. Like at the start of a
+// run, the case is ignored, so eg US works too. Whether the location exists
+// is only checked at the start of a run.
+func isLocation(location string) bool {
+	location = strings.ToLower(strings.TrimSpace(location))
+	return location == gcputil.LocationAll || location == gcputil.LocationGlobal ||
+		gcputil.IsMultiRegion(location) || gcputil.IsRegion(location) || gcputil.IsZone(location)
+}
+
+// Validate loads the config files and checks them for mistakes, which would
+// otherwise only be noticed during a run, if at all. Syntax errors are
+// returned as error, all other problems are collected.
+func Validate(catalog Catalog, vars Variables, paths ...string) ([]Problem, error) {
+	return (&Loader{Variables: vars}).Validate(catalog, paths...)
+}
+
+// Validate works like the function Validate, but reads the config files with
+// the loader.
+func (l *Loader) Validate(catalog Catalog, paths ...string) ([]Problem, error) {
+	_, err := l.Load(paths...)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := l.Files(paths...)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]*yaml.Node, len(files))
+	for i, path := range files {
+		raw, err := l.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var doc yaml.Node
+		err = yaml.Unmarshal(raw, &doc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if len(doc.Content) > 0 {
+			docs[i] = doc.Content[0]
+			expandNode(l.Variables, docs[i])
+		}
+	}
+
+	v := &validator{
+		catalog: catalog,
+		presets: map[string]bool{},
+	}
+
+	// Presets and restricted projects can be defined in any of the files.
+	for i, doc := range docs {
+		v.file = files[i]
+		v.definitions(doc)
+	}
+	for i, doc := range docs {
+		v.file = files[i]
+		v.root(doc)
+	}
+
+	order := map[string]int{}
+	for i, path := range files {
+		order[path] = i
+	}
+	sort.SliceStable(v.problems, func(i, j int) bool {
+		a, b := v.problems[i], v.problems[j]
+		if a.File != b.File {
+			return order[a.File] < order[b.File]
+		}
+		return a.Line < b.Line
+	})
+
+	if len(v.restricted) == 0 {
+		v.problems = append(v.problems, Problem{
+			Path:    "project-restricted-list",
+			Message: "must contain at least one project",
+		})
+	}
+
+	return v.problems, nil
+}
+
+// expandNode expands the variables in all scalars, like Load does. Undefined
+// variables were already reported by Load.
+func expandNode(vars Variables, node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode {
+		node.Value, _ = vars.Expand(node.Value)
+	}
+	for _, child := range node.Content {
+		expandNode(vars, child)
+	}
+}
+
+type validator struct {
+	catalog    Catalog
+	presets    map[string]bool
+	restricted []ProjectPattern
+	file       string
+	problems   []Problem
+}
+
+func (v *validator) report(node *yaml.Node, path string, format string, args ...interface{}) {
+	v.problems = append(v.problems, Problem{
+		File:    v.file,
+		Line:    node.Line,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) warn(node *yaml.Node, path string, format string, args ...interface{}) {
+	v.report(node, path, format, args...)
+	v.problems[len(v.problems)-1].Warning = true
+}
+
+// mapping calls fn for every key and value of a mapping node.
+func mapping(node *yaml.Node, fn func(key, value *yaml.Node)) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		fn(node.Content[i], node.Content[i+1])
+	}
+}
+
+// sequence calls fn for every item of a sequence node.
+func sequence(node *yaml.Node, fn func(i int, item *yaml.Node)) {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return
+	}
+	for i, item := range node.Content {
+		fn(i, item)
+	}
+}
+
+func lookup(node *yaml.Node, name string) *yaml.Node {
+	var found *yaml.Node
+	mapping(node, func(key, value *yaml.Node) {
+		if key.Value == name {
+			found = value
+		}
+	})
+	return found
+}
+
+func (v *validator) definitions(node *yaml.Node) {
+	mapping(lookup(node, "presets"), func(key, _ *yaml.Node) {
+		v.presets[key.Value] = true
+	})
+
+	sequence(lookup(node, "project-restricted-list"), func(i int, item *yaml.Node) {
+		pattern := ProjectPattern(item.Value)
+		err := pattern.Validate()
+		if err != nil {
+			v.report(item, fmt.Sprintf("project-restricted-list[%d]", i), "%v", err)
+		}
+		v.restricted = append(v.restricted, pattern)
+	})
+}
+
+func (v *validator) root(node *yaml.Node) {
+	mapping(node, func(key, value *yaml.Node) {
+		switch key.Value {
+		case "resource-types":
+			v.resourceTypes(value, key.Value)
+
+		case "projects":
+			mapping(value, func(key, value *yaml.Node) {
+				v.project(key, value, "projects."+key.Value)
+			})
+
+		case "presets":
+			mapping(value, func(key, value *yaml.Node) {
+				path := "presets." + key.Value
+				v.filters(lookup(value, "filters"), path+".filters")
+			})
+
+		case "filters":
+			v.filters(value, "filters")
+
+		case "feature-flags":
+			v.featureFlags(value, "feature-flags")
+
+		case "removal-parallelism":
+			mapping(value, func(key, value *yaml.Node) {
+				path := "removal-parallelism." + key.Value
+				if !v.knownAPI(key.Value) {
+					v.report(key, path, "unknown API '%s'", key.Value)
+				}
+				v.number(value, path, 1)
+			})
+
+		case "endpoints":
+			sequence(value, func(i int, region *yaml.Node) {
+				v.endpoints(region, fmt.Sprintf("endpoints[%d]", i))
+			})
+
+		case "retry-budgets":
+			mapping(value, func(key, value *yaml.Node) {
+				v.resourceType(key, "retry-budgets")
+				v.number(value, "retry-budgets."+key.Value, 0)
+			})
+		}
+	})
+}
+
+func (v *validator) project(key, node *yaml.Node, path string) {
+	pattern := ProjectPattern(key.Value)
+	err := pattern.Validate()
+	if err != nil {
+		v.report(key, path, "%v", err)
+	}
+
+	// Patterns might match restricted and unrestricted projects, so only
+	// exact project IDs are checked.
+	if pattern.Kind() == ProjectPatternExact {
+		for _, restricted := range v.restricted {
+			match, _ := restricted.Match(key.Value)
+			if match {
+				v.report(key, path, "the project is in the project-restricted-list and cannot be nuked")
+				break
+			}
+		}
+	}
+
+	sequence(lookup(node, "locations"), func(i int, item *yaml.Node) {
+		if !isLocation(item.Value) {
+			v.report(item, fmt.Sprintf("%s.locations[%d]", path, i),
+				"invalid location '%s', expected 'all', 'global', a multi-region like 'us', "+
+					"a region like 'us-east1' or a zone like 'us-east1-b'", item.Value)
+		}
+	})
+
+	sequence(lookup(node, "presets"), func(i int, item *yaml.Node) {
+		if !v.presets[item.Value] {
+			v.report(item, fmt.Sprintf("%s.presets[%d]", path, i), "unknown preset '%s'", item.Value)
+		}
+	})
+
+	v.resourceTypes(lookup(node, "resource-types"), path+".resource-types")
+	v.filters(lookup(node, "filters"), path+".filters")
+	v.featureFlags(lookup(node, "feature-flags"), path+".feature-flags")
+}
+
+func (v *validator) featureFlags(node *yaml.Node, path string) {
+	path = path + ".disable-deletion-protection"
+	mapping(lookup(node, "disable-deletion-protection"), func(key, _ *yaml.Node) {
+		info, ok := v.resourceType(key, path)
+		if ok && !info.Preparer {
+			v.report(key, path, "the resource type '%s' has no deletion protection", key.Value)
+		}
+	})
+}
+
+func (v *validator) resourceTypes(node *yaml.Node, path string) {
+	mapping(node, func(key, value *yaml.Node) {
+		sequence(value, func(_ int, item *yaml.Node) {
+			v.resourceType(item, path+"."+key.Value)
+		})
+	})
+}
+
+func (v *validator) resourceType(node *yaml.Node, path string) (ResourceTypeInfo, bool) {
+	name := node.Value
+	if current, ok := v.catalog.Aliases[name]; ok {
+		v.warn(node, path, "the resource type '%s' is deprecated, use '%s' instead", name, current)
+		name = current
+	}
+
+	info, ok := v.catalog.ResourceTypes[name]
+	if !ok {
+		v.report(node, path, "unknown resource type '%s'", node.Value)
+	}
+	return info, ok
+}
+
+func (v *validator) filters(node *yaml.Node, path string) {
+	mapping(node, func(key, value *yaml.Node) {
+		info, ok := v.resourceType(key, path)
+		if !ok {
+			return
+		}
+		sequence(value, func(i int, item *yaml.Node) {
+			v.filter(info, item, fmt.Sprintf("%s.%s[%d]", path, key.Value, i))
+		})
+	})
+}
+
+func (v *validator) filter(info ResourceTypeInfo, node *yaml.Node, path string) {
+	var filter Filter
+	err := node.Decode(&filter)
+	if err != nil {
+		v.report(node, path, "%v", err)
+		return
+	}
+
+	err = filter.validate()
+	if err != nil {
+		v.report(node, path, "%v", err)
+	}
+
+	if filter.IsGroup() {
+		for _, name := range []string{"all", "any"} {
+			sequence(lookup(node, name), func(i int, item *yaml.Node) {
+				v.filter(info, item, fmt.Sprintf("%s.%s[%d]", path, name, i))
+			})
+		}
+		if not := lookup(node, "not"); not != nil {
+			v.filter(info, not, path+".not")
+		}
+		return
+	}
+
+	switch {
+	case filter.Type == FilterTypeCEL:
+		// Expressions refer to all properties at once.
+	case filter.Property == "" && !info.LegacyID:
+		v.report(node, path, "the resource type requires a property for filters")
+	case filter.Property != "" && !info.HasProperty(filter.Property):
+		v.report(node, path, "unknown property '%s', expected one of %s",
+			filter.Property, strings.Join(info.Properties, ", "))
+	}
+}
+
+func (v *validator) endpoints(node *yaml.Node, path string) {
+	region := lookup(node, "region")
+	if region != nil && region.Value != "" && !isLocation(region.Value) {
+		v.report(region, path+".region", "invalid region '%s'", region.Value)
+	}
+
+	sequence(lookup(node, "services"), func(i int, item *yaml.Node) {
+		path := fmt.Sprintf("%s.services[%d]", path, i)
+
+		var service CustomService
+		err := item.Decode(&service)
+		if err != nil {
+			v.report(item, path, "%v", err)
+			return
+		}
+
+		if !v.knownAPI(service.Service) {
+			v.report(item, path, "unknown API '%s'", service.Service)
+		}
+		err = service.Validate()
+		if err != nil {
+			v.report(item, path, "%v", err)
+		}
+	})
+}
+
+func (v *validator) knownAPI(api string) bool {
+	for _, known := range v.catalog.APIs {
+		if known == api {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *validator) number(node *yaml.Node, path string, min int) {
+	value, err := strconv.Atoi(node.Value)
+	if err != nil || value < min {
+		v.report(node, path, "must be a number of at least %d", min)
+	}
+}
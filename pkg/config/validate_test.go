@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	catalog := Catalog{
+		ResourceTypes: map[string]ResourceTypeInfo{
+			"Bucket":          {Properties: []string{"CreationTime", "Name"}},
+			"ComputeInstance": {Properties: []string{"Name"}, Preparer: true},
+			"VPC":             {Properties: []string{"Name"}},
+		},
+		APIs: []string{"compute", "storage"},
+	}
+
+	problems, err := Validate(catalog, nil, "test-fixtures/invalid.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := "test-fixtures/invalid.yaml"
+	want := []Problem{
+		{file, 4, "project-restricted-list[1]", "invalid regex '/prod-(/': error parsing regexp: missing closing ): `^(?:prod-()$`", false},
+		{file, 9, "resource-types.targets", "unknown resource type 'Buckt'", false},
+		{file, 12, "projects.production-project", "the project is in the project-restricted-list and cannot be nuked", false},
+		{file, 16, "projects.production-project.locations[2]", "invalid location 'us-east-1', expected 'all', 'global', a multi-region like 'us', a region like 'us-east1' or a zone like 'us-east1-b'", false},
+		{file, 19, "projects.production-project.presets[1]", "unknown preset 'terrafrom'", false},
+		{file, 22, "projects.production-project.filters.Bucket[0]", "invalid regex 'state-(': error parsing regexp: missing closing ): `state-(`", false},
+		{file, 25, "projects.production-project.filters.Bucket[1]", "unknown property 'Nmae', expected one of CreationTime, Name", false},
+		{file, 29, "projects.production-project.filters.Bucket[3]", "invalid duration '7 days': time: unknown unit \" days\" in duration \"7 days\"", false},
+		{file, 33, "projects.production-project.filters.VPC[0]", "the resource type requires a property for filters", false},
+		{file, 38, "feature-flags.disable-deletion-protection", "unknown resource type 'SQLInstance'", false},
+		{file, 41, "removal-parallelism.compute", "must be a number of at least 1", false},
+		{file, 43, "removal-parallelism.spanner", "unknown API 'spanner'", false},
+		{file, 46, "retry-budgets.Bucket", "must be a number of at least 0", false},
+		{file, 52, "presets.terraform.filters.Bucket[0]", "invalid value 'yes please' for invert, expected true or false", false},
+		{file, 62, "endpoints[0].services[1]", "unknown API 'spanner'", false},
+		{file, 62, "endpoints[0].services[1]", "The endpoint of the service 'spanner' must be an http or https URL, but is 'localhost:9010'.", false},
+		{file, 66, "filters", "unknown resource type 'Bukcet'", false},
+		{file, 70, "filters.Bucket[0].all[0]", "unknown property 'Nmae', expected one of CreationTime, Name", false},
+		{file, 73, "filters.Bucket[0].all[1].not", "invalid regex 'tmp-(': error parsing regexp: missing closing ): `tmp-(`", false},
+		{file, 76, "filters.Bucket[1]", "a filter group must use only one of all, any and not", false},
+		{file, 82, "filters.Bucket[2]", "the lower bound '20' of between is greater than the upper bound '10'", false},
+	}
+
+	if len(problems) != len(want) {
+		t.Fatalf("Wrong number of problems. Want: %d. Have: %d: %v", len(want), len(problems), problems)
+	}
+	for i := range want {
+		if problems[i] != want[i] {
+			t.Errorf("Wrong problem.\nWant: %s\nHave: %s", want[i], problems[i])
+		}
+	}
+}
+
+func TestValidateUnknownKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte("project-restricted-list: [prod]\nprojets: {}\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Validate(Catalog{}, nil, path)
+	if err == nil {
+		t.Fatal("Expected an error for an unknown key.")
+	}
+}
+
+func TestValidateAliases(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`project-restricted-list: [prod]
+resource-types:
+  targets: [StorageBucket]
+projects:
+  dev:
+    filters:
+      StorageBucket:
+      - property: Name
+        value: keep
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	catalog := Catalog{
+		ResourceTypes: map[string]ResourceTypeInfo{
+			"Bucket": {Properties: []string{"Name"}},
+		},
+		Aliases: map[string]string{"StorageBucket": "Bucket"},
+	}
+
+	problems, err := Validate(catalog, nil, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message := "the resource type 'StorageBucket' is deprecated, use 'Bucket' instead"
+	want := []Problem{
+		{path, 3, "resource-types.targets", message, true},
+		{path, 7, "projects.dev.filters", message, true},
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("Wrong problems. Want: %v. Have: %v", want, problems)
+	}
+}
+
+func TestValidateLocations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`project-restricted-list: [prod]
+projects:
+  dev:
+    locations: [all, Global, US, eu, EUR4, us-east1, US-EAST1-B, us-east-1, usa]
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err := Validate(Catalog{}, nil, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message := "invalid location '%s', expected 'all', 'global', a multi-region like 'us', " +
+		"a region like 'us-east1' or a zone like 'us-east1-b'"
+	want := []Problem{
+		{path, 4, "projects.dev.locations[7]", fmt.Sprintf(message, "us-east-1"), false},
+		{path, 4, "projects.dev.locations[8]", fmt.Sprintf(message, "usa"), false},
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("Wrong problems. Want: %v. Have: %v", want, problems)
+	}
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+)
+
+// Variables are expanded in the values of config files with the syntax
+// ${NAME}. Variables which are not defined here are looked up in the
+// environment. $${ results in a literal ${.
+type Variables map[string]string
+
+// VariableProjectID is defined for every run with the ID of the nuked
+// project.
+const VariableProjectID = "PROJECT_ID"
+
+var variablePattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+func (v Variables) Lookup(name string) (string, bool) {
+	value, ok := v[name]
+	if ok {
+		return value, true
+	}
+	return os.LookupEnv(name)
+}
+
+func (v Variables) Expand(s string) (string, error) {
+	var err error
+
+	expanded := variablePattern.ReplaceAllStringFunc(s, func(match string) string {
+		if match == "$${" {
+			return "${"
+		}
+
+		name := match[2 : len(match)-1]
+		value, ok := v.Lookup(name)
+		if !ok && err == nil {
+			err = fmt.Errorf("The variable '%s' is not defined.", name)
+		}
+		return value
+	})
+
+	return expanded, err
+}
+
+// ExpandAll expands the variables in all strings of the given value, which
+// has to be a pointer. Map keys are expanded too, so eg ${PROJECT_ID} can be
+// used as key of the projects.
+func (v Variables) ExpandAll(value interface{}) error {
+	return v.expandValue(reflect.ValueOf(value))
+}
+
+func (v Variables) expandValue(value reflect.Value) error {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+		return v.expandValue(value.Elem())
+
+	case reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		elem := reflect.New(value.Elem().Type()).Elem()
+		elem.Set(value.Elem())
+		err := v.expandValue(elem)
+		if err != nil {
+			return err
+		}
+		value.Set(elem)
+
+	case reflect.String:
+		expanded, err := v.Expand(value.String())
+		if err != nil {
+			return err
+		}
+		value.SetString(expanded)
+
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if !value.Field(i).CanSet() {
+				continue
+			}
+			err := v.expandValue(value.Field(i))
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			err := v.expandValue(value.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		if value.IsNil() {
+			return nil
+		}
+
+		expanded := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			key := reflect.New(value.Type().Key()).Elem()
+			key.Set(iter.Key())
+			err := v.expandValue(key)
+			if err != nil {
+				return err
+			}
+
+			elem := reflect.New(value.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			err = v.expandValue(elem)
+			if err != nil {
+				return err
+			}
+
+			if expanded.MapIndex(key).IsValid() {
+				return fmt.Errorf("The key '%v' is defined twice after expanding variables.", key)
+			}
+			expanded.SetMapIndex(key, elem)
+		}
+		value.Set(expanded)
+	}
+
+	return nil
+}
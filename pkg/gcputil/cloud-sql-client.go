@@ -7,6 +7,7 @@ package gcputil
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"google.golang.org/api/option"
 	cloudsql "google.golang.org/api/sqladmin/v1beta4"
@@ -24,7 +25,7 @@ func NewSQLClient(ctx context.Context, opts ...option.ClientOption) (c *CloudSQL
 	c = &CloudSQLClient{}
 	c.client, err = cloudsql.NewService(ctx, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("Error creating CloudSQLClient: %v", err)
+		return nil, fmt.Errorf("Error creating CloudSQLClient: %w", err)
 	}
 	return
 }
@@ -33,10 +34,46 @@ func (c *CloudSQLClient) List(ctx context.Context, project string) (*cloudsql.In
 	return c.client.Instances.List(project).Context(ctx).Do()
 }
 
+func (c *CloudSQLClient) Get(ctx context.Context, project, dbInstance string) (*cloudsql.DatabaseInstance, error) {
+	return c.client.Instances.Get(project, dbInstance).Context(ctx).Do()
+}
+
 func (c *CloudSQLClient) Remove(ctx context.Context, project, dbInstance string) (*cloudsql.Operation, error) {
 	return c.client.Instances.Delete(project, dbInstance).Context(ctx).Do()
 }
 
+func (c *CloudSQLClient) DisableDeletionProtection(ctx context.Context, project, dbInstance string) (*cloudsql.Operation, error) {
+	instance := &cloudsql.DatabaseInstance{
+		Settings: &cloudsql.Settings{
+			DeletionProtectionEnabled: false,
+			ForceSendFields:           []string{"DeletionProtectionEnabled"},
+		},
+	}
+	return c.client.Instances.Patch(project, dbInstance, instance).Context(ctx).Do()
+}
+
+// WaitOperation polls the operation until it is done and returns its error.
+func (c *CloudSQLClient) WaitOperation(ctx context.Context, project string, op *cloudsql.Operation) error {
+	for op.Status != "DONE" {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+
+		var err error
+		op, err = c.GetOperation(ctx, project, op.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	if op.Error != nil && len(op.Error.Errors) > 0 {
+		return fmt.Errorf("Operation %s failed: %s", op.Name, op.Error.Errors[0].Message)
+	}
+	return nil
+}
+
 func (c *CloudSQLClient) GetOperation(ctx context.Context, project, operation string) (*cloudsql.Operation, error) {
 	return c.client.Operations.Get(project, operation).Context(ctx).Do()
 }
@@ -0,0 +1,111 @@
+package gcputil
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Transport is the protocol a client uses to talk to its API. It decides how
+// a custom endpoint gets passed to the client.
+type Transport int
+
+const (
+	TransportGRPC Transport = iota
+	TransportREST
+)
+
+// Endpoint overrides the URL of a Google Cloud API, eg to use Private Service
+// Connect, a regional endpoint or an emulator.
+type Endpoint struct {
+	URL                   string
+	TLSInsecureSkipVerify bool
+}
+
+// ClientOptions returns the options for creating a client of the given API.
+// They contain the credentials and the custom endpoint of the API, if there
+// is one.
+func (p *Project) ClientOptions(api string, transport Transport) ([]option.ClientOption, error) {
+	options := p.Creds.GetNewClientOptions()
+
+	endpoint, ok := p.Endpoints[api]
+	if !ok {
+		return options, nil
+	}
+
+	options, err := endpoint.clientOptions(p.GetContext(), transport, options)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint for the API %s: %w", api, err)
+	}
+	return options, nil
+}
+
+func (e Endpoint) clientOptions(ctx context.Context, transport Transport, options []option.ClientOption) ([]option.ClientOption, error) {
+	u, err := url.Parse(e.URL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("'%s' is not an http or https URL", e.URL)
+	}
+
+	// Emulators and fakes are usually served without TLS. Credentials are
+	// never sent over such a connection.
+	plain := u.Scheme == "http"
+	if plain {
+		options = []option.ClientOption{option.WithoutAuthentication()}
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: e.TLSInsecureSkipVerify}
+
+	switch transport {
+	case TransportGRPC:
+		// gRPC clients expect host and port instead of a URL.
+		host := u.Host
+		if u.Port() == "" {
+			port := "443"
+			if plain {
+				port = "80"
+			}
+			host = net.JoinHostPort(u.Hostname(), port)
+		}
+
+		creds := credentials.NewTLS(tlsConfig)
+		if plain {
+			creds = insecure.NewCredentials()
+		}
+
+		return append(options,
+			option.WithEndpoint(host),
+			option.WithGRPCDialOption(grpc.WithTransportCredentials(creds)),
+		), nil
+
+	case TransportREST:
+		if e.TLSInsecureSkipVerify && !plain {
+			// A custom HTTP client replaces the authentication of the
+			// client, so it has to be wrapped explicitly.
+			base := http.DefaultTransport.(*http.Transport).Clone()
+			base.TLSClientConfig = tlsConfig
+
+			authenticated, err := htransport.NewTransport(ctx, base, options...)
+			if err != nil {
+				return nil, err
+			}
+			options = append(options, option.WithHTTPClient(&http.Client{Transport: authenticated}))
+		}
+
+		return append(options, option.WithEndpoint(e.URL)), nil
+
+	default:
+		return nil, fmt.Errorf("unknown transport %d", transport)
+	}
+}
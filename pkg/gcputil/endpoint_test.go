@@ -0,0 +1,74 @@
+package gcputil
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	computepb "google.golang.org/genproto/googleapis/cloud/compute/v1"
+)
+
+func TestEndpointREST(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"name": "default"}`)
+	}))
+	defer server.Close()
+
+	project := NewProject(context.Background(), &Credentials{Project: "test-project"})
+	project.Endpoints = map[string]Endpoint{
+		"compute": {URL: server.URL},
+	}
+
+	options, err := project.ClientOptions("compute", TransportREST)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := compute.NewNetworksRESTClient(project.GetContext(), options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	network, err := client.Get(project.GetContext(), &computepb.GetNetworkRequest{
+		Project: "test-project",
+		Network: "default",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if network.GetName() != "default" {
+		t.Errorf("Wrong network. Want: default. Have: %s", network.GetName())
+	}
+	want := "/compute/v1/projects/test-project/global/networks/default"
+	if path != want {
+		t.Errorf("Wrong path. Want: %s. Have: %s", want, path)
+	}
+}
+
+func TestEndpointInvalid(t *testing.T) {
+	project := NewProject(context.Background(), &Credentials{Project: "test-project"})
+	project.Endpoints = map[string]Endpoint{
+		"pubsub": {URL: "localhost:8085"},
+	}
+
+	_, err := project.ClientOptions("pubsub", TransportGRPC)
+	if err == nil {
+		t.Errorf("Expected an error for an URL without scheme.")
+	}
+
+	options, err := project.ClientOptions("compute", TransportREST)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(options) != 0 {
+		t.Errorf("Wrong number of options. Want: 0. Have: %d", len(options))
+	}
+}
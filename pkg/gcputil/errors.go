@@ -1,5 +1,16 @@
 package gcputil
 
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
 type ErrSkipRequest string
 
 func (err ErrSkipRequest) Error() string {
@@ -11,3 +22,155 @@ type ErrUnknownEndpoint string
 func (err ErrUnknownEndpoint) Error() string {
 	return string(err)
 }
+
+// ErrPermanent is returned for requests which can never succeed, eg because
+// a locked retention cannot be removed, so they are not retried.
+type ErrPermanent string
+
+func (err ErrPermanent) Error() string {
+	return string(err)
+}
+
+// ErrorClass describes how a failed request to GCP should be handled.
+type ErrorClass int
+
+const (
+	// ErrorClassUnknown is used for errors which do not come from a GCP API.
+	ErrorClassUnknown ErrorClass = iota
+	// ErrorClassRetryable is used for errors which may disappear on their own,
+	// like exceeded quotas or resources which are still in use.
+	ErrorClassRetryable
+	// ErrorClassPermanent is used for errors which do not change by
+	// retrying, like missing permissions or invalid requests.
+	ErrorClassPermanent
+	// ErrorClassNotFound is used for resources which do not exist anymore.
+	ErrorClassNotFound
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassRetryable:
+		return "retryable"
+	case ErrorClassPermanent:
+		return "permanent"
+	case ErrorClassNotFound:
+		return "not found"
+	}
+	return "unknown"
+}
+
+// Reasons of googleapi errors, which are retryable regardless of the status
+// code. For example compute returns 400 for resources which are still in use
+// and 403 for exceeded rate limits.
+var retryableReasons = map[string]bool{
+	"resourceInUseByAnotherResource": true,
+	"resourceNotReady":               true,
+	"rateLimitExceeded":              true,
+	"userRateLimitExceeded":          true,
+	"quotaExceeded":                  true,
+	"backendError":                   true,
+}
+
+// ClassifyError classifies errors of the REST (googleapi) and gRPC clients.
+// Errors have to be wrapped with %w to be classified.
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ErrorClassUnknown
+	}
+
+	var permanent ErrPermanent
+	if errors.As(err, &permanent) {
+		return ErrorClassPermanent
+	}
+
+	// The storage client translates 404 responses into its own errors.
+	if errors.Is(err, storage.ErrBucketNotExist) || errors.Is(err, storage.ErrObjectNotExist) {
+		return ErrorClassNotFound
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return classifyHTTPError(apiErr)
+	}
+
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) && grpcErr.GRPCStatus() != nil {
+		return classifyGRPCCode(grpcErr.GRPCStatus().Code())
+	}
+
+	return ErrorClassUnknown
+}
+
+// IsNotFound checks whether the error says that the resource does not exist.
+func IsNotFound(err error) bool {
+	return ClassifyError(err) == ErrorClassNotFound
+}
+
+func classifyHTTPError(err *googleapi.Error) ErrorClass {
+	for _, item := range err.Errors {
+		if retryableReasons[item.Reason] {
+			return ErrorClassRetryable
+		}
+	}
+
+	switch err.Code {
+	case http.StatusNotFound:
+		return ErrorClassNotFound
+	case http.StatusTooManyRequests, http.StatusConflict, http.StatusPreconditionFailed,
+		http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return ErrorClassRetryable
+	case http.StatusBadRequest:
+		if isInUseMessage(err.Message) {
+			return ErrorClassRetryable
+		}
+		return ErrorClassPermanent
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusMethodNotAllowed,
+		http.StatusNotImplemented:
+		return ErrorClassPermanent
+	}
+
+	return ErrorClassUnknown
+}
+
+func classifyGRPCCode(code codes.Code) ErrorClass {
+	switch code {
+	case codes.NotFound:
+		return ErrorClassNotFound
+	case codes.ResourceExhausted, codes.Unavailable, codes.Aborted, codes.FailedPrecondition,
+		codes.DeadlineExceeded, codes.Internal:
+		return ErrorClassRetryable
+	case codes.PermissionDenied, codes.Unauthenticated, codes.InvalidArgument,
+		codes.Unimplemented, codes.OutOfRange:
+		return ErrorClassPermanent
+	}
+
+	return ErrorClassUnknown
+}
+
+func isInUseMessage(message string) bool {
+	message = strings.ToLower(message)
+	return strings.Contains(message, "in use") || strings.Contains(message, "is being used") ||
+		strings.Contains(message, "not ready")
+}
+
+// IsServiceDisabled checks whether the request failed, because the API is not
+// enabled in the project.
+func IsServiceDisabled(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		for _, item := range apiErr.Errors {
+			if item.Reason == "accessNotConfigured" {
+				return true
+			}
+		}
+	}
+
+	message := err.Error()
+	return strings.Contains(message, "SERVICE_DISABLED") ||
+		strings.Contains(message, "has not been used in project")
+}
@@ -0,0 +1,144 @@
+package gcputil
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{
+			name: "Plain",
+			err:  errors.New("something broke"),
+			want: ErrorClassUnknown,
+		},
+		{
+			name: "HTTPNotFound",
+			err:  &googleapi.Error{Code: 404},
+			want: ErrorClassNotFound,
+		},
+		{
+			name: "HTTPTooManyRequests",
+			err:  &googleapi.Error{Code: 429},
+			want: ErrorClassRetryable,
+		},
+		{
+			name: "HTTPConflict",
+			err:  &googleapi.Error{Code: 409},
+			want: ErrorClassRetryable,
+		},
+		{
+			name: "HTTPPermissionDenied",
+			err:  &googleapi.Error{Code: 403},
+			want: ErrorClassPermanent,
+		},
+		{
+			name: "HTTPRateLimitReason",
+			err: &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{
+				{Reason: "rateLimitExceeded"},
+			}},
+			want: ErrorClassRetryable,
+		},
+		{
+			name: "HTTPResourceInUse",
+			err: &googleapi.Error{Code: 400, Errors: []googleapi.ErrorItem{
+				{Reason: "resourceInUseByAnotherResource"},
+			}},
+			want: ErrorClassRetryable,
+		},
+		{
+			name: "HTTPInvalid",
+			err:  &googleapi.Error{Code: 400, Message: "Invalid value for field"},
+			want: ErrorClassPermanent,
+		},
+		{
+			name: "WrappedHTTP",
+			err:  fmt.Errorf("failed to list instances: %w", &googleapi.Error{Code: 503}),
+			want: ErrorClassRetryable,
+		},
+		{
+			name: "StorageObjectNotExist",
+			err:  storage.ErrObjectNotExist,
+			want: ErrorClassNotFound,
+		},
+		{
+			name: "GRPCNotFound",
+			err:  status.Error(codes.NotFound, "not found"),
+			want: ErrorClassNotFound,
+		},
+		{
+			name: "WrappedGRPCExhausted",
+			err:  fmt.Errorf("failed to list functions: %w", status.Error(codes.ResourceExhausted, "quota")),
+			want: ErrorClassRetryable,
+		},
+		{
+			name: "WrappedPermanent",
+			err:  fmt.Errorf("prepare failed: %w", ErrPermanent("the locked retention cannot be removed")),
+			want: ErrorClassPermanent,
+		},
+		{
+			name: "GRPCPermissionDenied",
+			err:  status.Error(codes.PermissionDenied, "denied"),
+			want: ErrorClassPermanent,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			have := ClassifyError(tc.err)
+			if have != tc.want {
+				t.Errorf("Wrong class. Want: %s. Have: %s", tc.want, have)
+			}
+		})
+	}
+}
+
+func TestIsServiceDisabled(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "Nil",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "HTTPReason",
+			err: &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{
+				{Reason: "accessNotConfigured"},
+			}},
+			want: true,
+		},
+		{
+			name: "GRPCMessage",
+			err: fmt.Errorf("failed to list functions: %w", status.Error(codes.PermissionDenied,
+				"Cloud Functions API has not been used in project 123 before or it is disabled.")),
+			want: true,
+		},
+		{
+			name: "PermissionDenied",
+			err:  status.Error(codes.PermissionDenied, "Permission 'run.services.list' denied"),
+			want: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if have := IsServiceDisabled(tc.err); have != tc.want {
+				t.Errorf("Wrong result. Want: %t. Have: %t", tc.want, have)
+			}
+		})
+	}
+}
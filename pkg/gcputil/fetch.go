@@ -0,0 +1,130 @@
+package gcputil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+)
+
+// MaxFetchSize limits the size of fetched files, so a wrong URL cannot fill
+// the memory.
+const MaxFetchSize = 10 << 20
+
+// FetchScope is the OAuth scope for fetching files. Reading objects from
+// Cloud Storage needs no more.
+const FetchScope = "https://www.googleapis.com/auth/devstorage.read_only"
+
+// fetchClient requests files from servers other than Google APIs. It only
+// follows redirects which stay on https.
+var fetchClient = &http.Client{CheckRedirect: checkHTTPSRedirect}
+
+// Fetch reads a file from Cloud Storage (gs://bucket/object) or from an
+// https URL. Requests to Google APIs are authenticated with the credentials,
+// other servers never see them.
+func (c *Credentials) Fetch(ctx context.Context, location string) ([]byte, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "gs":
+		return c.fetchObject(ctx, u)
+	case "https":
+		return c.fetchURL(ctx, u)
+	default:
+		return nil, fmt.Errorf("unsupported scheme '%s'", u.Scheme)
+	}
+}
+
+func (c *Credentials) fetchObject(ctx context.Context, u *url.URL) ([]byte, error) {
+	object := strings.TrimPrefix(u.Path, "/")
+	if u.Host == "" || object == "" {
+		return nil, fmt.Errorf("expected gs://bucket/object, but got '%s'", u)
+	}
+
+	client, err := storage.NewClient(ctx, c.GetNewClientOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	reader, err := client.Bucket(u.Host).Object(object).NewReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return readAll(reader)
+}
+
+func (c *Credentials) fetchURL(ctx context.Context, u *url.URL) ([]byte, error) {
+	client := fetchClient
+	if isGoogleAPI(u.Hostname()) {
+		options := append(c.GetNewClientOptions(), option.WithScopes(FetchScope))
+		authorized, _, err := htransport.NewClient(ctx, options...)
+		if err != nil {
+			return nil, err
+		}
+		// The transport adds the token to every request, so redirects must
+		// not lead to other servers.
+		authorized.CheckRedirect = checkGoogleRedirect
+		client = authorized
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", response.Status)
+	}
+
+	return readAll(response.Body)
+}
+
+func checkGoogleRedirect(request *http.Request, via []*http.Request) error {
+	if request.URL.Scheme != "https" || !isGoogleAPI(request.URL.Hostname()) {
+		return fmt.Errorf("refusing to follow the redirect to %s with credentials", request.URL.Redacted())
+	}
+	return checkHTTPSRedirect(request, via)
+}
+
+func checkHTTPSRedirect(request *http.Request, via []*http.Request) error {
+	if request.URL.Scheme != "https" {
+		return fmt.Errorf("refusing to follow the redirect to %s without https", request.URL.Redacted())
+	}
+	if len(via) >= 10 {
+		return fmt.Errorf("stopped after 10 redirects")
+	}
+	return nil
+}
+
+func isGoogleAPI(host string) bool {
+	return host == "googleapis.com" || strings.HasSuffix(host, ".googleapis.com")
+}
+
+func readAll(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxFetchSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxFetchSize {
+		return nil, fmt.Errorf("the file is larger than %d bytes", MaxFetchSize)
+	}
+	return data, nil
+}
@@ -0,0 +1,98 @@
+package gcputil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsGoogleAPI(t *testing.T) {
+	cases := map[string]bool{
+		"storage.googleapis.com":      true,
+		"googleapis.com":              true,
+		"example.com":                 false,
+		"storage.googleapis.com.evil": false,
+		"evilgoogleapis.com":          false,
+	}
+
+	for host, want := range cases {
+		have := isGoogleAPI(host)
+		if have != want {
+			t.Errorf("Wrong result for %s. Want: %t. Have: %t", host, want, have)
+		}
+	}
+}
+
+func TestCheckGoogleRedirect(t *testing.T) {
+	cases := map[string]bool{
+		"https://storage.googleapis.com/bucket/object?alt=media": true,
+		"https://example.com/nuke.yaml":                          false,
+		"http://storage.googleapis.com/bucket/object":            false,
+	}
+
+	for location, want := range cases {
+		request, err := http.NewRequest(http.MethodGet, location, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = checkGoogleRedirect(request, nil)
+		if (err == nil) != want {
+			t.Errorf("Wrong result for %s. Want allowed: %t. Have: %v", location, want, err)
+		}
+	}
+}
+
+func TestFetchErrors(t *testing.T) {
+	creds := &Credentials{}
+
+	for _, location := range []string{"ftp://example.com/nuke.yaml", "gs://bucket-only"} {
+		_, err := creds.Fetch(context.Background(), location)
+		if err == nil {
+			t.Errorf("Expected an error for %s but didn't get one.", location)
+		}
+	}
+}
+
+func TestFetchRedirects(t *testing.T) {
+	plainRequests := 0
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		plainRequests = plainRequests + 1
+		w.Write([]byte("targets: []"))
+	}))
+	defer plain.Close()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/downgrade":
+			http.Redirect(w, r, plain.URL+"/nuke.yaml", http.StatusFound)
+		case "/moved":
+			http.Redirect(w, r, "/nuke.yaml", http.StatusMovedPermanently)
+		default:
+			w.Write([]byte("targets: []"))
+		}
+	}))
+	defer server.Close()
+
+	previous := fetchClient
+	fetchClient = &http.Client{Transport: server.Client().Transport, CheckRedirect: previous.CheckRedirect}
+	t.Cleanup(func() { fetchClient = previous })
+
+	creds := &Credentials{}
+
+	data, err := creds.Fetch(context.Background(), server.URL+"/moved")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "targets: []" {
+		t.Errorf("Wrong content. Want: targets: []. Have: %s", data)
+	}
+
+	if _, err := creds.Fetch(context.Background(), server.URL+"/downgrade"); err == nil {
+		t.Errorf("Expected an error for a redirect to http but didn't get one.")
+	}
+	if plainRequests != 0 {
+		t.Errorf("The redirect to http was followed.")
+	}
+}
@@ -24,7 +24,7 @@ func NewIAMClient(ctx context.Context, opts ...option.ClientOption) (c *IAMClien
 	c = &IAMClient{}
 	c.client, err = iam.NewService(ctx, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("Error creating IAMClient: %v", err)
+		return nil, fmt.Errorf("Error creating IAMClient: %w", err)
 	}
 	return
 }
@@ -0,0 +1,181 @@
+package gcputil
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	computepb "google.golang.org/genproto/googleapis/cloud/compute/v1"
+)
+
+const (
+	// LocationGlobal is the location of resources which are not bound to a
+	// region.
+	LocationGlobal = "global"
+
+	// LocationAll is resolved to all regions of the project, global and the
+	// multi-regions.
+	LocationAll = "all"
+)
+
+// MultiRegions are the multi-regions of Cloud Storage and BigQuery.
+var MultiRegions = []string{"asia", "eu", "us"}
+
+// DualRegions are the predefined dual-regions of Cloud Storage. They have to
+// be configured explicitly.
+var DualRegions = []string{"asia1", "eur4", "eur5", "eur7", "eur8", "nam4"}
+
+var (
+	regionPattern = regexp.MustCompile(`^[a-z]+-[a-z]+[0-9]+$`)
+	zonePattern   = regexp.MustCompile(`^([a-z]+-[a-z]+[0-9]+)-[a-z]$`)
+)
+
+func IsRegion(location string) bool {
+	return regionPattern.MatchString(location)
+}
+
+func IsZone(location string) bool {
+	return zonePattern.MatchString(location)
+}
+
+func IsMultiRegion(location string) bool {
+	return contains(MultiRegions, strings.ToLower(location)) || contains(DualRegions, strings.ToLower(location))
+}
+
+// ZoneRegion returns the region of a zone, eg us-east1 for us-east1-b. The
+// zone may also be given as URL or as key of an aggregated list, like
+// zones/us-east1-b.
+func ZoneRegion(zone string) string {
+	match := zonePattern.FindStringSubmatch(path.Base(zone))
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// ZoneInLocations checks whether a zone is part of the locations, either
+// because the zone itself or its region is configured.
+func ZoneInLocations(zone string, locations []string) bool {
+	zone = path.Base(zone)
+	region := ZoneRegion(zone)
+
+	for _, location := range locations {
+		if location == zone || (region != "" && location == region) {
+			return true
+		}
+	}
+	return false
+}
+
+// LocationInList checks whether the location of a resource is configured.
+// Locations of Cloud Storage and BigQuery are upper case, so the case is
+// ignored.
+func LocationInList(location string, locations []string) bool {
+	for _, l := range locations {
+		if strings.EqualFold(l, location) {
+			return true
+		}
+	}
+	return false
+}
+
+// Regions are the regions available to a project with their zones.
+type Regions map[string][]string
+
+// ListRegions requests the regions of a project from the Compute Engine API.
+func ListRegions(ctx context.Context, project string, opts ...option.ClientOption) (Regions, error) {
+	client, err := compute.NewRegionsRESTClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compute regions client: %w", err)
+	}
+	defer client.Close()
+
+	regions := Regions{}
+	it := client.List(ctx, &computepb.ListRegionsRequest{Project: project})
+	for {
+		region, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list regions: %w", err)
+		}
+
+		zones := []string{}
+		for _, zone := range region.GetZones() {
+			zones = append(zones, path.Base(zone))
+		}
+		regions[region.GetName()] = zones
+	}
+
+	return regions, nil
+}
+
+func (r Regions) Names() []string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r Regions) HasZone(zone string) bool {
+	return contains(r[ZoneRegion(zone)], zone)
+}
+
+// Resolve replaces 'all' by global, the multi-regions and all regions and
+// checks that every other location exists. The order of the locations is
+// kept and duplicates are removed.
+func (r Regions) Resolve(locations []string) ([]string, error) {
+	resolved := []string{}
+	add := func(location string) {
+		if !contains(resolved, location) {
+			resolved = append(resolved, location)
+		}
+	}
+
+	for _, location := range locations {
+		location = strings.ToLower(strings.TrimSpace(location))
+		switch {
+		case location == LocationAll:
+			add(LocationGlobal)
+			for _, multiRegion := range MultiRegions {
+				add(multiRegion)
+			}
+			for _, region := range r.Names() {
+				add(region)
+			}
+
+		case location == LocationGlobal || IsMultiRegion(location):
+			add(location)
+
+		case IsZone(location) && r.HasZone(location):
+			add(location)
+
+		case r[location] != nil:
+			add(location)
+
+		default:
+			return nil, fmt.Errorf("The location '%s' does not exist. "+
+				"Use 'all', 'global', a multi-region, a region or a zone.", location)
+		}
+	}
+
+	return resolved, nil
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
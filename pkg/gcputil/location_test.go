@@ -0,0 +1,115 @@
+package gcputil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestZoneInLocations(t *testing.T) {
+	cases := []struct {
+		zone      string
+		locations []string
+		want      bool
+	}{
+		{"zones/us-east1-b", []string{"us-east1"}, true},
+		{"zones/us-east10-a", []string{"us-east1"}, false},
+		{"zones/us-east1-b", []string{"us-east10"}, false},
+		{"zones/us-east1-b", []string{"us-east1-c"}, false},
+		{"zones/us-east1-b", []string{"global", "us-east1-b"}, true},
+		{"regions/us-east1", []string{"us-east1"}, true},
+		{"https://www.googleapis.com/compute/v1/projects/p/zones/europe-west1-d", []string{"europe-west1"}, true},
+		{"global", []string{"us-east1"}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.zone, func(t *testing.T) {
+			have := ZoneInLocations(tc.zone, tc.locations)
+			if have != tc.want {
+				t.Errorf("Wrong result for %v. Want: %t. Have: %t", tc.locations, tc.want, have)
+			}
+		})
+	}
+}
+
+func TestLocationInList(t *testing.T) {
+	locations := []string{"us", "europe-west1"}
+
+	cases := map[string]bool{
+		"US":           true,
+		"EU":           false,
+		"EUROPE-WEST1": true,
+		"europe-west1": true,
+		"US-EAST1":     false,
+		"NAM4":         false,
+	}
+
+	for location, want := range cases {
+		have := LocationInList(location, locations)
+		if have != want {
+			t.Errorf("Wrong result for %s. Want: %t. Have: %t", location, want, have)
+		}
+	}
+}
+
+func TestRegionsResolve(t *testing.T) {
+	regions := Regions{
+		"us-east1":     {"us-east1-b", "us-east1-c"},
+		"europe-west1": {"europe-west1-b"},
+	}
+
+	cases := []struct {
+		name      string
+		locations []string
+		want      []string
+		fail      bool
+	}{
+		{
+			name:      "regions",
+			locations: []string{"global", "us-east1", "US-EAST1", "eu"},
+			want:      []string{"global", "us-east1", "eu"},
+		},
+		{
+			name:      "all",
+			locations: []string{"us-east1", "all"},
+			want:      []string{"us-east1", "global", "asia", "eu", "us", "europe-west1"},
+		},
+		{
+			name:      "zone",
+			locations: []string{"us-east1-c", "nam4"},
+			want:      []string{"us-east1-c", "nam4"},
+		},
+		{
+			name:      "unknown zone",
+			locations: []string{"us-east1-z"},
+			fail:      true,
+		},
+		{
+			name:      "typo",
+			locations: []string{"us-east-1"},
+			fail:      true,
+		},
+		{
+			name:      "unknown region",
+			locations: []string{"us-east10"},
+			fail:      true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			have, err := regions.Resolve(tc.locations)
+			if tc.fail {
+				if err == nil {
+					t.Fatalf("Expected an error, but got %v.", have)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(have, tc.want) {
+				t.Errorf("Wrong locations. Want: %v. Have: %v", tc.want, have)
+			}
+		})
+	}
+}
@@ -10,8 +10,14 @@ type Project struct {
 
 	Creds     *Credentials
 	Locations []string
-	clients   sync.Map
-	ctx       context.Context
+	Endpoints map[string]Endpoint
+
+	// ScopeStorageLocations limits the resources of Cloud Storage and
+	// BigQuery to the Locations, too.
+	ScopeStorageLocations bool
+
+	clients sync.Map
+	ctx     context.Context
 }
 
 func (p *Project) GetClient(resourceType string) (GCPClient, bool) {
@@ -30,17 +36,23 @@ func (p *Project) GetContext() context.Context {
 	return p.ctx
 }
 
+// SetContext replaces the context which is used for all further requests. It
+// must not be called while requests are in flight.
+func (p *Project) SetContext(ctx context.Context) {
+	p.ctx = ctx
+}
+
 func (p *Project) CloseClients() {
 	p.clients.Range(func(k, client interface{}) bool {
 		client.(GCPClient).Close()
 		return true
 	})
 }
-func NewProject(creds *Credentials) *Project {
+func NewProject(ctx context.Context, creds *Credentials) *Project {
 	return &Project{
 		Name:    creds.Project,
 		Creds:   creds,
 		clients: sync.Map{},
-		ctx:     context.Background(),
+		ctx:     ctx,
 	}
 }
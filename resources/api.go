@@ -0,0 +1,24 @@
+package resources
+
+// Names of the Google Cloud APIs used by the resources. They match the
+// service names of the API endpoints (eg compute.googleapis.com).
+const (
+	APIArtifactRegistry = "artifactregistry"
+	APIBigQuery         = "bigquery"
+	APICloudBuild       = "cloudbuild"
+	APICloudFunctions   = "cloudfunctions"
+	APICloudKMS         = "cloudkms"
+	APICloudScheduler   = "cloudscheduler"
+	APICompute          = "compute"
+	APIContainer        = "container"
+	APIFile             = "file"
+	APIIAM              = "iam"
+	APIPubSub           = "pubsub"
+	APIRedis            = "redis"
+	APIRun              = "run"
+	APISecretManager    = "secretmanager"
+	APISQLAdmin         = "sqladmin"
+	APIStorage          = "storage"
+	APIVPCAccess        = "vpcaccess"
+	APIWorkflows        = "workflows"
+)
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"path"
-	"strings"
 
 	artifactregistry "cloud.google.com/go/artifactregistry/apiv1"
 	artifactregistrypb "cloud.google.com/go/artifactregistry/apiv1/artifactregistrypb"
@@ -23,7 +22,10 @@ type ArtifactRegistry struct {
 }
 
 func init() {
-	register(ResourceTypeArtifactRegistry, GetArtifactRegistryClient, ListArtifactRegistry)
+	register(ResourceTypeArtifactRegistry, GetArtifactRegistryClient, ListArtifactRegistry,
+		withAPI(APIArtifactRegistry),
+		withPrototype(&ArtifactRegistry{}),
+	)
 }
 
 func GetArtifactRegistryClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -31,9 +33,13 @@ func GetArtifactRegistryClient(project *gcputil.Project) (gcputil.GCPClient, err
 		return client, nil
 	}
 
-	client, err := artifactregistry.NewClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APIArtifactRegistry, gcputil.TransportGRPC)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create artifact registry client: %v", err)
+		return nil, err
+	}
+	client, err := artifactregistry.NewClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create artifact registry client: %w", err)
 	}
 	project.AddClient(ResourceTypeArtifactRegistry, client)
 	return client, nil
@@ -45,8 +51,8 @@ func ListArtifactRegistry(project *gcputil.Project, client gcputil.GCPClient) ([
 	resources := make([]Resource, 0)
 
 	for _, location := range project.Locations {
-		// global isn't a valid location for Artifact Registries
-		if strings.ToLower(location) == "global" {
+		// only regions are valid locations for Artifact Registries
+		if !gcputil.IsRegion(location) {
 			continue
 		}
 		req := &artifactregistrypb.ListRepositoriesRequest{
@@ -60,7 +66,7 @@ func ListArtifactRegistry(project *gcputil.Project, client gcputil.GCPClient) ([
 			}
 
 			if err != nil {
-				return nil, fmt.Errorf("failed to list artifact repositories: %v", err)
+				return nil, fmt.Errorf("failed to list artifact repositories: %w", err)
 			}
 			resources = append(resources, &ArtifactRegistry{
 				name:     path.Base(repository.Name),
@@ -90,6 +96,17 @@ func (x *ArtifactRegistry) GetOperationError(_ context.Context) error {
 	return nil
 }
 
+func (x *ArtifactRegistry) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	artifactRegistryClient := client.(*artifactregistry.Client)
+
+	req := &artifactregistrypb.GetRepositoryRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s/repositories/%s", x.project, x.location, x.name),
+	}
+	_, err := artifactRegistryClient.GetRepository(ctx, req)
+
+	return existence(err)
+}
+
 func (x *ArtifactRegistry) String() string {
 	return x.name
 }
@@ -3,10 +3,9 @@ package resources
 import (
 	"context"
 	"fmt"
-	"path"
 
 	"cloud.google.com/go/bigquery"
-	"google.golang.org/api/iterator"
+	bq "google.golang.org/api/bigquery/v2"
 
 	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
 	"github.com/dshelley66/gcp-nuke/pkg/types"
@@ -15,12 +14,16 @@ import (
 const ResourceTypeBigqueryDataset = "BigqueryDataset"
 
 type BigqueryDataset struct {
-	id      string
-	project string
+	id       string
+	project  string
+	location string
 }
 
 func init() {
-	register(ResourceTypeBigqueryDataset, GetBigqueryDatasetClient, ListBigqueryDataset)
+	register(ResourceTypeBigqueryDataset, GetBigqueryDatasetClient, ListBigqueryDataset,
+		withAPI(APIBigQuery),
+		withPrototype(&BigqueryDataset{}),
+	)
 }
 
 func GetBigqueryDatasetClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -28,33 +31,55 @@ func GetBigqueryDatasetClient(project *gcputil.Project) (gcputil.GCPClient, erro
 		return client, nil
 	}
 
-	client, err := bigquery.NewClient(project.GetContext(), project.Name, project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APIBigQuery, gcputil.TransportREST)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create bigquery datasets client: %v", err)
+		return nil, err
+	}
+	client, err := bigquery.NewClient(project.GetContext(), project.Name, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bigquery datasets client: %w", err)
 	}
 	project.AddClient(ResourceTypeBigqueryDataset, client)
 	return client, nil
 }
 
-func ListBigqueryDataset(project *gcputil.Project, client gcputil.GCPClient) ([]Resource, error) {
-	bigqueryClient := client.(*bigquery.Client)
+// ListBigqueryDataset lists the datasets with the REST API, since the
+// iterator of the client drops the location of the datasets, which would
+// take one request per dataset otherwise.
+func ListBigqueryDataset(project *gcputil.Project, _ gcputil.GCPClient) ([]Resource, error) {
+	options, err := project.ClientOptions(APIBigQuery, gcputil.TransportREST)
+	if err != nil {
+		return nil, err
+	}
+	service, err := bq.NewService(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bigquery service: %w", err)
+	}
 
 	resources := make([]Resource, 0)
 
-	it := bigqueryClient.Datasets(project.GetContext())
-	for {
-		dataset, err := it.Next()
-		if err == iterator.Done {
-			break
+	err = service.Datasets.List(project.Name).Pages(project.GetContext(), func(page *bq.DatasetList) error {
+		for _, dataset := range page.Datasets {
+			if dataset.DatasetReference == nil {
+				continue
+			}
+			id := dataset.DatasetReference.DatasetId
+
+			// Datasets are in a region or a multi-region, eg US.
+			if !inStorageLocations(project, ResourceTypeBigqueryDataset, id, dataset.Location) {
+				continue
+			}
+
+			resources = append(resources, &BigqueryDataset{
+				id:       id,
+				project:  project.Name,
+				location: dataset.Location,
+			})
 		}
-
-		if err != nil {
-			return nil, fmt.Errorf("failed to list bigquery datasets: %v", err)
-		}
-		resources = append(resources, &BigqueryDataset{
-			id:      path.Base(dataset.DatasetID),
-			project: project.Name,
-		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bigquery datasets: %w", err)
 	}
 
 	return resources, nil
@@ -74,6 +99,14 @@ func (x *BigqueryDataset) GetOperationError(_ context.Context) error {
 	return nil
 }
 
+func (x *BigqueryDataset) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	bigqueryClient := client.(*bigquery.Client)
+
+	_, err := bigqueryClient.Dataset(x.id).Metadata(ctx)
+
+	return existence(err)
+}
+
 func (x *BigqueryDataset) String() string {
 	return x.id
 }
@@ -82,6 +115,7 @@ func (x *BigqueryDataset) Properties() types.Properties {
 	properties := types.NewProperties()
 	properties.Set("ID", x.id)
 	properties.Set("Project", x.project)
+	properties.Set("Location", x.location)
 
 	return properties
 }
@@ -0,0 +1,53 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
+)
+
+func TestListBigqueryDataset(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = requests + 1
+		if r.URL.Path != "/bigquery/v2/projects/analytics/datasets" {
+			http.Error(w, r.URL.Path, http.StatusNotImplemented)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"datasets": []map[string]interface{}{
+				{"datasetReference": map[string]string{"projectId": "analytics", "datasetId": "events"}, "location": "US"},
+				{"datasetReference": map[string]string{"projectId": "analytics", "datasetId": "reports"}, "location": "EU"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	project := gcputil.NewProject(context.Background(), &gcputil.Credentials{Project: "analytics"})
+	project.Endpoints = map[string]gcputil.Endpoint{APIBigQuery: {URL: server.URL + "/bigquery/v2/"}}
+	project.Locations = []string{"us"}
+	project.ScopeStorageLocations = true
+
+	datasets, err := ListBigqueryDataset(project, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The location is part of the list response, so no dataset is requested
+	// on its own.
+	if requests != 1 {
+		t.Errorf("Wrong number of requests. Want: 1. Have: %d", requests)
+	}
+	if len(datasets) != 1 {
+		t.Fatalf("Wrong number of datasets. Want: 1. Have: %d", len(datasets))
+	}
+
+	dataset := datasets[0].(*BigqueryDataset)
+	if dataset.id != "events" || dataset.location != "US" {
+		t.Errorf("Wrong dataset. Want: events in US. Have: %s in %s", dataset.id, dataset.location)
+	}
+}
@@ -21,7 +21,10 @@ type BigqueryJob struct {
 }
 
 func init() {
-	register(ResourceTypeBigqueryJob, GetBigqueryJobClient, ListBigqueryJob)
+	register(ResourceTypeBigqueryJob, GetBigqueryJobClient, ListBigqueryJob,
+		withAPI(APIBigQuery),
+		withPrototype(&BigqueryJob{}),
+	)
 }
 
 func GetBigqueryJobClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -29,9 +32,13 @@ func GetBigqueryJobClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := bigquery.NewClient(project.GetContext(), project.Name, project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APIBigQuery, gcputil.TransportREST)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create bigquery datasets client: %v", err)
+		return nil, err
+	}
+	client, err := bigquery.NewClient(project.GetContext(), project.Name, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bigquery datasets client: %w", err)
 	}
 	project.AddClient(ResourceTypeBigqueryJob, client)
 	return client, nil
@@ -50,7 +57,10 @@ func ListBigqueryJob(project *gcputil.Project, client gcputil.GCPClient) ([]Reso
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list bigquery jobs: %v", err)
+			return nil, fmt.Errorf("failed to list bigquery jobs: %w", err)
+		}
+		if !inStorageLocations(project, ResourceTypeBigqueryJob, job.ID(), job.Location()) {
+			continue
 		}
 		resources = append(resources, &BigqueryJob{
 			id:       path.Base(job.ID()),
@@ -80,6 +90,14 @@ func (x *BigqueryJob) GetOperationError(_ context.Context) error {
 	return nil
 }
 
+func (x *BigqueryJob) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	bigqueryClient := client.(*bigquery.Client)
+
+	_, err := bigqueryClient.JobFromIDLocation(ctx, x.id, x.location)
+
+	return existence(err)
+}
+
 func (x *BigqueryJob) String() string {
 	return x.id
 }
@@ -23,7 +23,10 @@ type CloudBuildTrigger struct {
 }
 
 func init() {
-	register(ResourceTypeCloudBuildTrigger, GetCloudBuildTriggerClient, ListCloudBuildTriggers)
+	register(ResourceTypeCloudBuildTrigger, GetCloudBuildTriggerClient, ListCloudBuildTriggers,
+		withAPI(APICloudBuild),
+		withPrototype(&CloudBuildTrigger{}),
+	)
 }
 
 func GetCloudBuildTriggerClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -31,9 +34,13 @@ func GetCloudBuildTriggerClient(project *gcputil.Project) (gcputil.GCPClient, er
 		return client, nil
 	}
 
-	client, err := cloudbuild.NewClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APICloudBuild, gcputil.TransportGRPC)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create cloud build trigger client: %v", err)
+		return nil, err
+	}
+	client, err := cloudbuild.NewClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloud build trigger client: %w", err)
 	}
 	project.AddClient(ResourceTypeCloudBuildTrigger, client)
 	return client, nil
@@ -45,10 +52,10 @@ func ListCloudBuildTriggers(project *gcputil.Project, client gcputil.GCPClient)
 	resources := make([]Resource, 0)
 	var req *cloudbuildpb.ListBuildTriggersRequest
 	for _, location := range project.Locations {
-		// global isn't a valid location for cloud build
-		// if strings.ToLower(location) == "global" {
-		// 	continue
-		// }
+		// global and regions are valid locations for cloud build
+		if location != gcputil.LocationGlobal && !gcputil.IsRegion(location) {
+			continue
+		}
 		req = &cloudbuildpb.ListBuildTriggersRequest{
 			Parent: fmt.Sprintf("projects/%s/locations/%s", project.Name, location),
 		}
@@ -61,7 +68,7 @@ func ListCloudBuildTriggers(project *gcputil.Project, client gcputil.GCPClient)
 			}
 
 			if err != nil {
-				return nil, fmt.Errorf("failed to list cloud build triggers: %v", err)
+				return nil, fmt.Errorf("failed to list cloud build triggers: %w", err)
 			}
 			resources = append(resources, &CloudBuildTrigger{
 				name:         path.Base(resp.GetName()),
@@ -92,6 +99,17 @@ func (x *CloudBuildTrigger) GetOperationError(ctx context.Context) error {
 	return nil
 }
 
+func (x *CloudBuildTrigger) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	cloudBuildClient := client.(*cloudbuild.Client)
+
+	req := &cloudbuildpb.GetBuildTriggerRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s/triggers/%s", project.Name, x.region, x.name),
+	}
+	_, err := cloudBuildClient.GetBuildTrigger(ctx, req)
+
+	return existence(err)
+}
+
 func (x *CloudBuildTrigger) String() string {
 	return x.name
 }
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"path"
-	"strings"
 	"time"
 
 	run "cloud.google.com/go/run/apiv2"
@@ -27,7 +26,10 @@ type CloudRunJob struct {
 }
 
 func init() {
-	register(ResourceTypeCloudRunJob, GetCloudRunJobClient, ListCloudRunJobs)
+	register(ResourceTypeCloudRunJob, GetCloudRunJobClient, ListCloudRunJobs,
+		withAPI(APIRun),
+		withPrototype(&CloudRunJob{}),
+	)
 }
 
 func GetCloudRunJobClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -35,9 +37,13 @@ func GetCloudRunJobClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := run.NewJobsClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APIRun, gcputil.TransportGRPC)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create cloud run job client: %v", err)
+		return nil, err
+	}
+	client, err := run.NewJobsClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloud run job client: %w", err)
 	}
 	project.AddClient(ResourceTypeCloudRunJob, client)
 	return client, nil
@@ -49,8 +55,8 @@ func ListCloudRunJobs(project *gcputil.Project, client gcputil.GCPClient) ([]Res
 	resources := make([]Resource, 0)
 	var req *runpb.ListJobsRequest
 	for _, location := range project.Locations {
-		// global isn't a valid location for cloud run
-		if strings.ToLower(location) == "global" {
+		// only regions are valid locations for cloud run
+		if !gcputil.IsRegion(location) {
 			continue
 		}
 		req = &runpb.ListJobsRequest{
@@ -65,7 +71,7 @@ func ListCloudRunJobs(project *gcputil.Project, client gcputil.GCPClient) ([]Res
 			}
 
 			if err != nil {
-				return nil, fmt.Errorf("failed to list cloud run jobs: %v", err)
+				return nil, fmt.Errorf("failed to list cloud run jobs: %w", err)
 			}
 			resources = append(resources, &CloudRunJob{
 				name:         path.Base(resp.GetName()),
@@ -105,6 +111,29 @@ func getRunJobOperationError(ctx context.Context, op *run.DeleteJobOperation) (e
 	return err
 }
 
+func (x *CloudRunJob) OperationName() string {
+	if x.operation == nil {
+		return ""
+	}
+	return x.operation.Name()
+}
+
+func (x *CloudRunJob) ResumeOperation(project *gcputil.Project, client gcputil.GCPClient, name string) error {
+	x.operation = client.(*run.JobsClient).DeleteJobOperation(name)
+	return nil
+}
+
+func (x *CloudRunJob) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	cloudRunClient := client.(*run.JobsClient)
+
+	req := &runpb.GetJobRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s/jobs/%s", project.Name, x.region, x.name),
+	}
+	_, err := cloudRunClient.GetJob(ctx, req)
+
+	return existence(err)
+}
+
 func (x *CloudRunJob) String() string {
 	return x.name
 }
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"path"
-	"strings"
 	"time"
 
 	run "cloud.google.com/go/run/apiv2"
@@ -27,7 +26,10 @@ type CloudRunService struct {
 }
 
 func init() {
-	register(ResourceTypeCloudRunService, GetCloudRunServiceClient, ListCloudRunServices)
+	register(ResourceTypeCloudRunService, GetCloudRunServiceClient, ListCloudRunServices,
+		withAPI(APIRun),
+		withPrototype(&CloudRunService{}),
+	)
 }
 
 func GetCloudRunServiceClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -35,9 +37,13 @@ func GetCloudRunServiceClient(project *gcputil.Project) (gcputil.GCPClient, erro
 		return client, nil
 	}
 
-	client, err := run.NewServicesClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APIRun, gcputil.TransportGRPC)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create cloud run service client: %v", err)
+		return nil, err
+	}
+	client, err := run.NewServicesClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloud run service client: %w", err)
 	}
 	project.AddClient(ResourceTypeCloudRunService, client)
 	return client, nil
@@ -49,8 +55,8 @@ func ListCloudRunServices(project *gcputil.Project, client gcputil.GCPClient) ([
 	resources := make([]Resource, 0)
 	var req *runpb.ListServicesRequest
 	for _, location := range project.Locations {
-		// global isn't a valid location for cloud run
-		if strings.ToLower(location) == "global" {
+		// only regions are valid locations for cloud run
+		if !gcputil.IsRegion(location) {
 			continue
 		}
 		req = &runpb.ListServicesRequest{
@@ -65,7 +71,7 @@ func ListCloudRunServices(project *gcputil.Project, client gcputil.GCPClient) ([
 			}
 
 			if err != nil {
-				return nil, fmt.Errorf("failed to list cloud run services: %v", err)
+				return nil, fmt.Errorf("failed to list cloud run services: %w", err)
 			}
 			resources = append(resources, &CloudRunService{
 				name:         path.Base(resp.GetName()),
@@ -105,6 +111,29 @@ func getRunServiceOperationError(ctx context.Context, op *run.DeleteServiceOpera
 	return err
 }
 
+func (x *CloudRunService) OperationName() string {
+	if x.operation == nil {
+		return ""
+	}
+	return x.operation.Name()
+}
+
+func (x *CloudRunService) ResumeOperation(project *gcputil.Project, client gcputil.GCPClient, name string) error {
+	x.operation = client.(*run.ServicesClient).DeleteServiceOperation(name)
+	return nil
+}
+
+func (x *CloudRunService) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	cloudRunClient := client.(*run.ServicesClient)
+
+	req := &runpb.GetServiceRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s/services/%s", project.Name, x.region, x.name),
+	}
+	_, err := cloudRunClient.GetService(ctx, req)
+
+	return existence(err)
+}
+
 func (x *CloudRunService) String() string {
 	return x.name
 }
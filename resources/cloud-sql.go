@@ -18,12 +18,16 @@ type CloudSQL struct {
 	location     string
 	dbVersion    string
 	project      string
+	protected    bool
 	operation    *cloudsql.Operation
 	sqlClient    *gcputil.CloudSQLClient
 }
 
 func init() {
-	register(ResourceTypeCloudSQL, GetCloudSQLClient, ListCloudSQLs)
+	register(ResourceTypeCloudSQL, GetCloudSQLClient, ListCloudSQLs,
+		withAPI(APISQLAdmin),
+		withPrototype(&CloudSQL{}),
+	)
 }
 
 func GetCloudSQLClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -31,9 +35,13 @@ func GetCloudSQLClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := gcputil.NewSQLClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APISQLAdmin, gcputil.TransportREST)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create sql client: %v", err)
+		return nil, err
+	}
+	client, err := gcputil.NewSQLClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sql client: %w", err)
 	}
 	project.AddClient(ResourceTypeCloudSQL, client)
 	return client, nil
@@ -46,17 +54,22 @@ func ListCloudSQLs(project *gcputil.Project, client gcputil.GCPClient) ([]Resour
 
 	resp, err := cloudSQLClient.List(project.GetContext(), project.Name)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list SQL instances: %v", err)
+		return nil, fmt.Errorf("failed to list SQL instances: %w", err)
 	}
 	for _, instance := range resp.Items {
-		resources = append(resources, &CloudSQL{
+		sql := &CloudSQL{
 			name:         instance.Name,
 			creationDate: instance.CreateTime,
-			labels:       instance.Settings.UserLabels,
 			location:     instance.Region,
 			dbVersion:    instance.DatabaseVersion,
 			project:      instance.Project,
-		})
+		}
+		// The settings are optional in the response, so they may be missing.
+		if instance.Settings != nil {
+			sql.labels = instance.Settings.UserLabels
+			sql.protected = instance.Settings.DeletionProtectionEnabled
+		}
+		resources = append(resources, sql)
 	}
 	return resources, nil
 }
@@ -72,6 +85,25 @@ func (x *CloudSQL) Remove(project *gcputil.Project, client gcputil.GCPClient) (e
 	return nil
 }
 
+func (x *CloudSQL) Prepare(project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	if !x.protected {
+		return false, nil
+	}
+
+	sqlClient := client.(*gcputil.CloudSQLClient)
+
+	op, err := sqlClient.DisableDeletionProtection(project.GetContext(), project.Name, x.name)
+	if err != nil {
+		return false, err
+	}
+	if err := sqlClient.WaitOperation(project.GetContext(), project.Name, op); err != nil {
+		return false, err
+	}
+
+	x.protected = false
+	return true, nil
+}
+
 func (x *CloudSQL) GetOperationError(ctx context.Context) error {
 	if x.operation != nil {
 		if op, err := x.sqlClient.GetOperation(ctx, x.project, x.operation.Name); err == nil {
@@ -87,6 +119,27 @@ func (x *CloudSQL) GetOperationError(ctx context.Context) error {
 	return nil
 }
 
+func (x *CloudSQL) OperationName() string {
+	if x.operation == nil {
+		return ""
+	}
+	return x.operation.Name
+}
+
+func (x *CloudSQL) ResumeOperation(project *gcputil.Project, client gcputil.GCPClient, name string) error {
+	x.sqlClient = client.(*gcputil.CloudSQLClient)
+	x.operation = &cloudsql.Operation{Name: name}
+	return nil
+}
+
+func (x *CloudSQL) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	sqlClient := client.(*gcputil.CloudSQLClient)
+
+	_, err := sqlClient.Get(ctx, project.Name, x.name)
+
+	return existence(err)
+}
+
 func (x *CloudSQL) String() string {
 	return x.name
 }
@@ -97,6 +150,7 @@ func (x *CloudSQL) Properties() types.Properties {
 	properties.Set("CreationDate", x.creationDate)
 	properties.Set("Location", x.location)
 	properties.Set("DBVersion", x.dbVersion)
+	properties.Set("DeletionProtection", x.protected)
 
 	for labelKey, label := range x.labels {
 		properties.SetTag(labelKey, label)
@@ -19,12 +19,17 @@ type ComputeDisk struct {
 	zone         string
 	creationDate string
 	status       string
+	sizeGB       int64
 	labels       map[string]string
-	operation    *compute.Operation
+	computeOperation
 }
 
 func init() {
-	register(ResourceTypeComputeDisk, GetComputeDiskClient, ListComputeDisks)
+	register(ResourceTypeComputeDisk, GetComputeDiskClient, ListComputeDisks,
+		withAPI(APICompute),
+		dependsOn(ResourceTypeComputeInstance),
+		withPrototype(&ComputeDisk{}),
+	)
 }
 
 func GetComputeDiskClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -32,9 +37,13 @@ func GetComputeDiskClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := compute.NewDisksRESTClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APICompute, gcputil.TransportREST)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create compute disks client: %v", err)
+		return nil, err
+	}
+	client, err := compute.NewDisksRESTClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compute disks client: %w", err)
 	}
 	project.AddClient(ResourceTypeComputeDisk, client)
 	return client, nil
@@ -56,7 +65,7 @@ func ListComputeDisks(project *gcputil.Project, client gcputil.GCPClient) ([]Res
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list compute disks: %v", err)
+			return nil, fmt.Errorf("failed to list compute disks: %w", err)
 		}
 		if ZoneInRegionList(resp.Key, project.Locations) {
 			for _, instance := range resp.Value.GetDisks() {
@@ -64,6 +73,7 @@ func ListComputeDisks(project *gcputil.Project, client gcputil.GCPClient) ([]Res
 					name:         instance.GetName(),
 					zone:         path.Base(instance.GetZone()),
 					status:       instance.GetStatus(),
+					sizeGB:       instance.GetSizeGb(),
 					creationDate: instance.GetCreationTimestamp(),
 					labels:       instance.GetLabels(),
 				})
@@ -90,8 +100,17 @@ func (x *ComputeDisk) Remove(project *gcputil.Project, client gcputil.GCPClient)
 	return nil
 }
 
-func (x *ComputeDisk) GetOperationError(ctx context.Context) error {
-	return getComputeOperationError(ctx, x.operation)
+func (x *ComputeDisk) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	disksClient := client.(*compute.DisksClient)
+
+	req := &computepb.GetDiskRequest{
+		Project: project.Name,
+		Zone:    x.zone,
+		Disk:    x.name,
+	}
+	_, err := disksClient.Get(ctx, req)
+
+	return existence(err)
 }
 
 func (x *ComputeDisk) String() string {
@@ -103,6 +122,7 @@ func (x *ComputeDisk) Properties() types.Properties {
 	properties.Set("Name", x.name)
 	properties.Set("Zone", x.zone)
 	properties.Set("Status", x.status)
+	properties.Set("SizeGB", x.sizeGB)
 	properties.Set("CreationDate", x.creationDate)
 
 	for labelKey, label := range x.labels {
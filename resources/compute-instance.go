@@ -21,11 +21,15 @@ type ComputeInstance struct {
 	status       string
 	machineType  string
 	labels       map[string]string
-	operation    *compute.Operation
+	protected    bool
+	computeOperation
 }
 
 func init() {
-	register(ResourceTypeComputeInstance, GetComputeInstanceClient, ListComputeInstances)
+	register(ResourceTypeComputeInstance, GetComputeInstanceClient, ListComputeInstances,
+		withAPI(APICompute),
+		withPrototype(&ComputeInstance{}),
+	)
 }
 
 func GetComputeInstanceClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -33,9 +37,13 @@ func GetComputeInstanceClient(project *gcputil.Project) (gcputil.GCPClient, erro
 		return client, nil
 	}
 
-	client, err := compute.NewInstancesRESTClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APICompute, gcputil.TransportREST)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create instances client: %v", err)
+		return nil, err
+	}
+	client, err := compute.NewInstancesRESTClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instances client: %w", err)
 	}
 	project.AddClient(ResourceTypeComputeInstance, client)
 	return client, nil
@@ -57,7 +65,7 @@ func ListComputeInstances(project *gcputil.Project, client gcputil.GCPClient) ([
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list instances: %v", err)
+			return nil, fmt.Errorf("failed to list instances: %w", err)
 		}
 		if ZoneInRegionList(resp.Key, project.Locations) {
 			for _, instance := range resp.Value.GetInstances() {
@@ -68,6 +76,7 @@ func ListComputeInstances(project *gcputil.Project, client gcputil.GCPClient) ([
 					machineType:  path.Base(instance.GetMachineType()),
 					creationDate: instance.GetCreationTimestamp(),
 					labels:       instance.GetLabels(),
+					protected:    instance.GetDeletionProtection(),
 				})
 			}
 		}
@@ -92,8 +101,44 @@ func (x *ComputeInstance) Remove(project *gcputil.Project, client gcputil.GCPCli
 	return nil
 }
 
-func (x *ComputeInstance) GetOperationError(ctx context.Context) error {
-	return getComputeOperationError(ctx, x.operation)
+func (x *ComputeInstance) Prepare(project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	if !x.protected {
+		return false, nil
+	}
+
+	instancesClient := client.(*compute.InstancesClient)
+
+	protected := false
+	req := &computepb.SetDeletionProtectionInstanceRequest{
+		Project:            project.Name,
+		Zone:               x.zone,
+		Resource:           x.name,
+		DeletionProtection: &protected,
+	}
+
+	op, err := instancesClient.SetDeletionProtection(project.GetContext(), req)
+	if err != nil {
+		return false, err
+	}
+	if err := op.Wait(project.GetContext()); err != nil {
+		return false, err
+	}
+
+	x.protected = false
+	return true, nil
+}
+
+func (x *ComputeInstance) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	instancesClient := client.(*compute.InstancesClient)
+
+	req := &computepb.GetInstanceRequest{
+		Project:  project.Name,
+		Zone:     x.zone,
+		Instance: x.name,
+	}
+	_, err := instancesClient.Get(ctx, req)
+
+	return existence(err)
 }
 
 func (x *ComputeInstance) String() string {
@@ -107,6 +152,7 @@ func (x *ComputeInstance) Properties() types.Properties {
 	properties.Set("Status", x.status)
 	properties.Set("MachineType", x.machineType)
 	properties.Set("CreationDate", x.creationDate)
+	properties.Set("DeletionProtection", x.protected)
 
 	for labelKey, label := range x.labels {
 		properties.SetTag(labelKey, label)
@@ -0,0 +1,175 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
+	computepb "google.golang.org/genproto/googleapis/cloud/compute/v1"
+)
+
+const (
+	computeGlobalOperationsClient = "ComputeGlobalOperations"
+	computeRegionOperationsClient = "ComputeRegionOperations"
+	computeZoneOperationsClient   = "ComputeZoneOperations"
+)
+
+// computeOperation is the delete operation of a compute resource. The
+// operation handles of the client library cannot be recreated from a name, so
+// a resumed operation is polled through the operations client of its scope.
+type computeOperation struct {
+	operation *compute.Operation
+	project   *gcputil.Project
+	resumed   string
+}
+
+func (o *computeOperation) GetOperationError(ctx context.Context) error {
+	if o.resumed != "" {
+		return o.pollResumed(ctx)
+	}
+	return getComputeOperationError(ctx, o.operation)
+}
+
+// OperationName returns the name of the operation including its scope, eg
+// "zones/us-central1-a/operations/operation-123".
+func (o *computeOperation) OperationName() string {
+	if o.resumed != "" {
+		return o.resumed
+	}
+	if o.operation == nil {
+		return ""
+	}
+
+	op := o.operation.Proto()
+	switch {
+	case op.GetZone() != "":
+		return path.Join("zones", path.Base(op.GetZone()), "operations", op.GetName())
+	case op.GetRegion() != "":
+		return path.Join("regions", path.Base(op.GetRegion()), "operations", op.GetName())
+	}
+	return path.Join("global", "operations", op.GetName())
+}
+
+func (o *computeOperation) ResumeOperation(project *gcputil.Project, _ gcputil.GCPClient, name string) error {
+	if _, _, _, err := parseComputeOperationName(name); err != nil {
+		return err
+	}
+
+	o.project = project
+	o.resumed = name
+	return nil
+}
+
+func (o *computeOperation) pollResumed(ctx context.Context) error {
+	scope, location, name, err := parseComputeOperationName(o.resumed)
+	if err != nil {
+		return err
+	}
+
+	var op *computepb.Operation
+	switch scope {
+	case "global":
+		client, err := getComputeOperationsClient(o.project, computeGlobalOperationsClient)
+		if err != nil {
+			return err
+		}
+		op, err = client.(*compute.GlobalOperationsClient).Get(ctx, &computepb.GetGlobalOperationRequest{
+			Project:   o.project.Name,
+			Operation: name,
+		})
+		if err != nil {
+			return err
+		}
+	case "regions":
+		client, err := getComputeOperationsClient(o.project, computeRegionOperationsClient)
+		if err != nil {
+			return err
+		}
+		op, err = client.(*compute.RegionOperationsClient).Get(ctx, &computepb.GetRegionOperationRequest{
+			Project:   o.project.Name,
+			Region:    location,
+			Operation: name,
+		})
+		if err != nil {
+			return err
+		}
+	case "zones":
+		client, err := getComputeOperationsClient(o.project, computeZoneOperationsClient)
+		if err != nil {
+			return err
+		}
+		op, err = client.(*compute.ZoneOperationsClient).Get(ctx, &computepb.GetZoneOperationRequest{
+			Project:   o.project.Name,
+			Zone:      location,
+			Operation: name,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if op.GetStatus() == computepb.Operation_DONE && op.GetError() != nil {
+		return fmt.Errorf("Delete error on '%s': %s", op.GetTargetLink(), op.GetHttpErrorMessage())
+	}
+	return nil
+}
+
+// parseComputeOperationName splits an operation name like
+// "regions/us-central1/operations/operation-123" into its scope, location and
+// name. Global operations have no location.
+func parseComputeOperationName(operation string) (scope, location, name string, err error) {
+	parts := strings.Split(operation, "/")
+	switch {
+	case len(parts) == 3 && parts[0] == "global" && parts[1] == "operations" && parts[2] != "":
+		return parts[0], "", parts[2], nil
+	case len(parts) == 4 && (parts[0] == "regions" || parts[0] == "zones") &&
+		parts[1] != "" && parts[2] == "operations" && parts[3] != "":
+		return parts[0], parts[1], parts[3], nil
+	}
+	return "", "", "", fmt.Errorf("invalid compute operation '%s'", operation)
+}
+
+func getComputeOperationsClient(project *gcputil.Project, key string) (gcputil.GCPClient, error) {
+	if client, ok := project.GetClient(key); ok {
+		return client, nil
+	}
+
+	options, err := project.ClientOptions(APICompute, gcputil.TransportREST)
+	if err != nil {
+		return nil, err
+	}
+
+	var client gcputil.GCPClient
+	switch key {
+	case computeGlobalOperationsClient:
+		client, err = compute.NewGlobalOperationsRESTClient(project.GetContext(), options...)
+	case computeRegionOperationsClient:
+		client, err = compute.NewRegionOperationsRESTClient(project.GetContext(), options...)
+	default:
+		client, err = compute.NewZoneOperationsRESTClient(project.GetContext(), options...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compute operations client: %w", err)
+	}
+	project.AddClient(key, client)
+	return client, nil
+}
+
+func getComputeOperationError(ctx context.Context, op *compute.Operation) error {
+	if op != nil {
+		if err := op.Poll(ctx); err == nil {
+			if op.Done() {
+				if op.Proto().GetHttpErrorStatusCode() != http.StatusOK {
+					return fmt.Errorf("Delete error on '%s': %s", op.Proto().GetTargetLink(), op.Proto().GetHttpErrorMessage())
+				}
+			}
+		} else {
+			return err
+		}
+	}
+	return nil
+}
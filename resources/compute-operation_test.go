@@ -0,0 +1,107 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
+)
+
+func TestParseComputeOperationName(t *testing.T) {
+	cases := []struct {
+		operation string
+		scope     string
+		location  string
+		name      string
+		invalid   bool
+	}{
+		{operation: "global/operations/operation-1", scope: "global", name: "operation-1"},
+		{operation: "regions/us-central1/operations/operation-2", scope: "regions", location: "us-central1", name: "operation-2"},
+		{operation: "zones/us-central1-a/operations/operation-3", scope: "zones", location: "us-central1-a", name: "operation-3"},
+		{operation: "operation-4", invalid: true},
+		{operation: "zones//operations/operation-5", invalid: true},
+		{operation: "projects/test/global/operations/operation-6", invalid: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.operation, func(t *testing.T) {
+			scope, location, name, err := parseComputeOperationName(tc.operation)
+			if tc.invalid {
+				if err == nil {
+					t.Fatalf("Expected an error for an invalid operation.")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if scope != tc.scope || location != tc.location || name != tc.name {
+				t.Errorf("Wrong operation. Want: %s %s %s. Have: %s %s %s",
+					tc.scope, tc.location, tc.name, scope, location, name)
+			}
+		})
+	}
+}
+
+func TestResumeComputeOperation(t *testing.T) {
+	operations := map[string]map[string]interface{}{
+		"/compute/v1/projects/test/zones/us-central1-a/operations/running": {
+			"name": "running", "status": "RUNNING",
+		},
+		"/compute/v1/projects/test/global/operations/failed": {
+			"name": "failed", "status": "DONE", "targetLink": "networks/default",
+			"httpErrorStatusCode": 400, "httpErrorMessage": "BAD REQUEST",
+			"error": map[string]interface{}{"errors": []map[string]string{{"code": "RESOURCE_IN_USE_BY_ANOTHER_RESOURCE"}}},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		operation, ok := operations[r.URL.Path]
+		if !ok {
+			http.Error(w, r.URL.Path, http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(operation)
+	}))
+	defer server.Close()
+
+	project := gcputil.NewProject(context.Background(), &gcputil.Credentials{Project: "test"})
+	project.Endpoints = map[string]gcputil.Endpoint{APICompute: {URL: server.URL}}
+
+	cases := []struct {
+		resource  OperationResumer
+		operation string
+		fails     bool
+		notFound  bool
+	}{
+		{resource: &ComputeDisk{name: "data"}, operation: "zones/us-central1-a/operations/running"},
+		{resource: &Vpc{name: "default"}, operation: "global/operations/failed", fails: true},
+		{resource: &Subnet{name: "default"}, operation: "regions/us-central1/operations/expired", fails: true, notFound: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.operation, func(t *testing.T) {
+			if err := tc.resource.ResumeOperation(project, nil, tc.operation); err != nil {
+				t.Fatal(err)
+			}
+			if tc.resource.OperationName() != tc.operation {
+				t.Errorf("Wrong operation name. Want: %s. Have: %s", tc.operation, tc.resource.OperationName())
+			}
+
+			err := tc.resource.GetOperationError(context.Background())
+			if tc.fails != (err != nil) {
+				t.Errorf("Wrong operation error. Want failure: %v. Have: %v", tc.fails, err)
+			}
+			// An expired operation is left to the existence check.
+			if tc.notFound != gcputil.IsNotFound(err) {
+				t.Errorf("Wrong not found error. Want: %v. Have: %v", tc.notFound, err)
+			}
+		})
+	}
+
+	if err := (&Route{}).ResumeOperation(project, nil, "operation-1"); err == nil {
+		t.Errorf("Expected an error for an operation without scope.")
+	}
+}
@@ -22,7 +22,10 @@ type FilestoreBackup struct {
 }
 
 func init() {
-	register(ResourceTypeFilestoreBackup, GetFilestoreBackupClient, ListFilestoreBackup)
+	register(ResourceTypeFilestoreBackup, GetFilestoreBackupClient, ListFilestoreBackup,
+		withAPI(APIFile),
+		withPrototype(&FilestoreBackup{}),
+	)
 }
 
 func GetFilestoreBackupClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -30,9 +33,13 @@ func GetFilestoreBackupClient(project *gcputil.Project) (gcputil.GCPClient, erro
 		return client, nil
 	}
 
-	client, err := filestore.NewCloudFilestoreManagerClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APIFile, gcputil.TransportGRPC)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create filestore client: %v", err)
+		return nil, err
+	}
+	client, err := filestore.NewCloudFilestoreManagerClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create filestore client: %w", err)
 	}
 	project.AddClient(ResourceTypeFilestoreBackup, client)
 	return client, nil
@@ -54,7 +61,7 @@ func ListFilestoreBackup(project *gcputil.Project, client gcputil.GCPClient) ([]
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list filestore backups: %v", err)
+			return nil, fmt.Errorf("failed to list filestore backups: %w", err)
 		}
 		_, loc := path.Split(path.Dir(path.Dir(backup.Name)))
 
@@ -84,6 +91,17 @@ func (x *FilestoreBackup) GetOperationError(_ context.Context) error {
 	return nil
 }
 
+func (x *FilestoreBackup) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	filestoreClient := client.(*filestore.CloudFilestoreManagerClient)
+
+	req := &filestorepb.GetBackupRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s/backups/%s", x.project, x.location, x.name),
+	}
+	_, err := filestoreClient.GetBackup(ctx, req)
+
+	return existence(err)
+}
+
 func (x *FilestoreBackup) String() string {
 	return x.name
 }
@@ -22,7 +22,10 @@ type FilestoreInstance struct {
 }
 
 func init() {
-	register(ResourceTypeFilestoreInstance, GetFilestoreInstanceClient, ListFilestoreInstance)
+	register(ResourceTypeFilestoreInstance, GetFilestoreInstanceClient, ListFilestoreInstance,
+		withAPI(APIFile),
+		withPrototype(&FilestoreInstance{}),
+	)
 }
 
 func GetFilestoreInstanceClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -30,9 +33,13 @@ func GetFilestoreInstanceClient(project *gcputil.Project) (gcputil.GCPClient, er
 		return client, nil
 	}
 
-	client, err := filestore.NewCloudFilestoreManagerClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APIFile, gcputil.TransportGRPC)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create filestore client: %v", err)
+		return nil, err
+	}
+	client, err := filestore.NewCloudFilestoreManagerClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create filestore client: %w", err)
 	}
 	project.AddClient(ResourceTypeFilestoreInstance, client)
 	return client, nil
@@ -54,7 +61,7 @@ func ListFilestoreInstance(project *gcputil.Project, client gcputil.GCPClient) (
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list filestore instances: %v", err)
+			return nil, fmt.Errorf("failed to list filestore instances: %w", err)
 		}
 		_, loc := path.Split(path.Dir(path.Dir(instance.Name)))
 
@@ -85,6 +92,17 @@ func (x *FilestoreInstance) GetOperationError(_ context.Context) error {
 	return nil
 }
 
+func (x *FilestoreInstance) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	filestoreClient := client.(*filestore.CloudFilestoreManagerClient)
+
+	req := &filestorepb.GetInstanceRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s/instances/%s", x.project, x.location, x.name),
+	}
+	_, err := filestoreClient.GetInstance(ctx, req)
+
+	return existence(err)
+}
+
 func (x *FilestoreInstance) String() string {
 	return x.name
 }
@@ -3,6 +3,7 @@ package resources
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
@@ -14,14 +15,20 @@ import (
 const ResourceTypeFirewall = "Firewall"
 
 type Firewall struct {
-	name         string
-	network      string
-	creationDate string
-	operation    *compute.Operation
+	name              string
+	network           string
+	creationDate      string
+	sourceRanges      []string
+	destinationRanges []string
+	priority          int32
+	computeOperation
 }
 
 func init() {
-	register(ResourceTypeFirewall, GetFirewallClient, ListFirewalls)
+	register(ResourceTypeFirewall, GetFirewallClient, ListFirewalls,
+		withAPI(APICompute),
+		withPrototype(&Firewall{}),
+	)
 }
 
 func GetFirewallClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -29,9 +36,13 @@ func GetFirewallClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := compute.NewFirewallsRESTClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APICompute, gcputil.TransportREST)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create firewall client: %v", err)
+		return nil, err
+	}
+	client, err := compute.NewFirewallsRESTClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create firewall client: %w", err)
 	}
 	project.AddClient(ResourceTypeFirewall, client)
 	return client, nil
@@ -54,12 +65,15 @@ func ListFirewalls(project *gcputil.Project, client gcputil.GCPClient) ([]Resour
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list firewalls: %v", err)
+			return nil, fmt.Errorf("failed to list firewalls: %w", err)
 		}
 		resources = append(resources, &Firewall{
-			name:         *resp.Name,
-			network:      *resp.Network,
-			creationDate: *resp.CreationTimestamp,
+			name:              *resp.Name,
+			network:           *resp.Network,
+			creationDate:      *resp.CreationTimestamp,
+			sourceRanges:      resp.GetSourceRanges(),
+			destinationRanges: resp.GetDestinationRanges(),
+			priority:          resp.GetPriority(),
 		})
 	}
 	return resources, nil
@@ -81,8 +95,16 @@ func (x *Firewall) Remove(project *gcputil.Project, client gcputil.GCPClient) (e
 	return nil
 }
 
-func (x *Firewall) GetOperationError(ctx context.Context) error {
-	return getComputeOperationError(ctx, x.operation)
+func (x *Firewall) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	firewallsClient := client.(*compute.FirewallsClient)
+
+	req := &computepb.GetFirewallRequest{
+		Firewall: x.name,
+		Project:  project.Name,
+	}
+	_, err := firewallsClient.Get(ctx, req)
+
+	return existence(err)
 }
 
 func (x *Firewall) String() string {
@@ -93,6 +115,9 @@ func (x *Firewall) Properties() types.Properties {
 	properties := types.NewProperties()
 	properties.Set("Name", x.name)
 	properties.Set("CreationDate", x.creationDate)
+	properties.Set("SourceRanges", strings.Join(x.sourceRanges, ","))
+	properties.Set("DestinationRanges", strings.Join(x.destinationRanges, ","))
+	properties.Set("Priority", x.priority)
 
 	return properties
 }
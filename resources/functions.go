@@ -22,7 +22,10 @@ type Function struct {
 }
 
 func init() {
-	register(ResourceTypeFunction, GetFunctionClient, ListFunction)
+	register(ResourceTypeFunction, GetFunctionClient, ListFunction,
+		withAPI(APICloudFunctions),
+		withPrototype(&Function{}),
+	)
 }
 
 func GetFunctionClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -30,9 +33,13 @@ func GetFunctionClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := functions.NewFunctionClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APICloudFunctions, gcputil.TransportGRPC)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create functions client: %v", err)
+		return nil, err
+	}
+	client, err := functions.NewFunctionClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create functions client: %w", err)
 	}
 	project.AddClient(ResourceTypeFunction, client)
 	return client, nil
@@ -54,7 +61,7 @@ func ListFunction(project *gcputil.Project, client gcputil.GCPClient) ([]Resourc
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list functions: %v", err)
+			return nil, fmt.Errorf("failed to list functions: %w", err)
 		}
 		_, loc := path.Split(path.Dir(path.Dir(function.Name)))
 
@@ -85,6 +92,17 @@ func (x *Function) GetOperationError(_ context.Context) error {
 	return nil
 }
 
+func (x *Function) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	functionsClient := client.(*functions.FunctionClient)
+
+	req := &functionspb.GetFunctionRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s/functions/%s", x.project, x.location, x.name),
+	}
+	_, err := functionsClient.GetFunction(ctx, req)
+
+	return existence(err)
+}
+
 func (x *Function) String() string {
 	return x.name
 }
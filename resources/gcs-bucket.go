@@ -21,7 +21,11 @@ type Bucket struct {
 }
 
 func init() {
-	register(ResourceTypeBucket, GetGCSClient, ListBuckets)
+	register(ResourceTypeBucket, GetGCSClient, ListBuckets,
+		withAPI(APIStorage),
+		dependsOn(ResourceTypeBucketObject),
+		withPrototype(&Bucket{}),
+	)
 }
 
 func GetGCSClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -29,9 +33,13 @@ func GetGCSClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := storage.NewClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APIStorage, gcputil.TransportREST)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create storage client: %v", err)
+		return nil, err
+	}
+	client, err := storage.NewClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage client: %w", err)
 	}
 	project.AddClient(ResourceTypeBucket, client)
 	return client, nil
@@ -50,7 +58,11 @@ func ListBuckets(project *gcputil.Project, client gcputil.GCPClient) ([]Resource
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list buckets: %v", err)
+			return nil, fmt.Errorf("failed to list buckets: %w", err)
+		}
+		// Buckets are in a region, a dual-region or a multi-region, eg US.
+		if !inStorageLocations(project, ResourceTypeBucket, resp.Name, resp.Location) {
+			continue
 		}
 		resources = append(resources, &Bucket{
 			name:         resp.Name,
@@ -78,6 +90,14 @@ func (b *Bucket) GetOperationError(_ context.Context) error {
 	return nil
 }
 
+func (b *Bucket) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	storageClient := client.(*storage.Client)
+
+	_, err := storageClient.Bucket(b.name).Attrs(ctx)
+
+	return existence(err)
+}
+
 func (b *Bucket) String() string {
 	return b.name
 }
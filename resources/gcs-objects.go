@@ -3,6 +3,7 @@ package resources
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"cloud.google.com/go/storage"
@@ -20,10 +21,20 @@ type BucketObject struct {
 	bucket       string
 	creationDate string
 	bucketLabels map[string]string
+	holds        bool
+	retention    string
+
+	// bucketRetention is the mode of the retention policy of the bucket. The
+	// objects cannot be removed as long as an unlocked policy applies to
+	// them, so it is removed while preparing the objects.
+	bucketRetention string
 }
 
 func init() {
-	register(ResourceTypeBucketObject, GetGCSClient, ListBucketObjects)
+	register(ResourceTypeBucketObject, GetGCSClient, ListBucketObjects,
+		withAPI(APIStorage),
+		withPrototype(&BucketObject{}),
+	)
 }
 
 func ListBucketObjects(project *gcputil.Project, client gcputil.GCPClient) ([]Resource, error) {
@@ -39,7 +50,10 @@ func ListBucketObjects(project *gcputil.Project, client gcputil.GCPClient) ([]Re
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list buckets: %v", err)
+			return nil, fmt.Errorf("failed to list buckets: %w", err)
+		}
+		if !inStorageLocations(project, ResourceTypeBucketObject, bucket.Name+"/*", bucket.Location) {
+			continue
 		}
 
 		query := &storage.Query{
@@ -54,7 +68,7 @@ func ListBucketObjects(project *gcputil.Project, client gcputil.GCPClient) ([]Re
 			fmt.Printf("Object %s | %s | %d", objAttrs.Name, objAttrs.Deleted, objAttrs.Generation)
 
 			if err != nil {
-				return nil, fmt.Errorf("failed to list bucket objects for %s: %v", bucket.Name, err)
+				return nil, fmt.Errorf("failed to list bucket objects for %s: %w", bucket.Name, err)
 			}
 			if !bucket.VersioningEnabled && !objAttrs.Deleted.IsZero() {
 				continue
@@ -65,6 +79,10 @@ func ListBucketObjects(project *gcputil.Project, client gcputil.GCPClient) ([]Re
 				bucket:       objAttrs.Bucket,
 				creationDate: objAttrs.Created.Format(time.RFC3339),
 				bucketLabels: bucket.Labels,
+				holds:        objAttrs.EventBasedHold || objAttrs.TemporaryHold,
+				retention:    objectRetentionMode(objAttrs.Retention),
+
+				bucketRetention: bucketRetentionMode(bucket.RetentionPolicy),
 			})
 		}
 	}
@@ -83,10 +101,107 @@ func (b *BucketObject) Remove(project *gcputil.Project, client gcputil.GCPClient
 	return nil
 }
 
+// Prepare releases the holds of the object and removes its retention, if it
+// is not locked. An unlocked retention policy of the bucket is removed as well,
+// since the bucket is only removed after all of its objects.
+func (b *BucketObject) Prepare(project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	if !b.holds && b.retention == "" && b.bucketRetention != "Unlocked" {
+		return false, nil
+	}
+	if b.retention == "Locked" {
+		return false, gcputil.ErrPermanent(fmt.Sprintf("The locked retention of %s cannot be removed.", b.name))
+	}
+
+	storageClient := client.(*storage.Client)
+
+	if b.bucketRetention == "Unlocked" {
+		err := removeBucketRetention(project, storageClient, b.bucket)
+		if err != nil {
+			return false, err
+		}
+		b.bucketRetention = ""
+	}
+
+	if !b.holds && b.retention == "" {
+		return true, nil
+	}
+
+	bucketObject := storageClient.Bucket(b.bucket).Object(b.name).Generation(b.generation)
+	update := storage.ObjectAttrsToUpdate{
+		EventBasedHold: false,
+		TemporaryHold:  false,
+	}
+	if b.retention != "" {
+		update.Retention = &storage.ObjectRetention{}
+		bucketObject = bucketObject.OverrideUnlockedRetention(true)
+	}
+
+	_, err := bucketObject.Update(project.GetContext(), update)
+	if err != nil {
+		return false, err
+	}
+
+	b.holds = false
+	b.retention = ""
+	return true, nil
+}
+
+// removedBucketRetentions records the buckets whose retention policy was
+// removed, so it is only removed once for all of their objects.
+var removedBucketRetentions sync.Map
+
+func removeBucketRetention(project *gcputil.Project, client *storage.Client, bucket string) error {
+	key := project.Name + "/" + bucket
+	if _, ok := removedBucketRetentions.Load(key); ok {
+		return nil
+	}
+
+	// A retention period of zero removes the policy.
+	update := storage.BucketAttrsToUpdate{
+		RetentionPolicy: &storage.RetentionPolicy{},
+	}
+	_, err := client.Bucket(bucket).Update(project.GetContext(), update)
+	if err != nil {
+		return err
+	}
+
+	removedBucketRetentions.Store(key, true)
+	return nil
+}
+
+// bucketRetentionMode returns whether the retention policy of a bucket is
+// locked. A locked policy cannot be removed, but it only protects objects
+// until their retention period passed, so their removal is still attempted.
+func bucketRetentionMode(policy *storage.RetentionPolicy) string {
+	switch {
+	case policy == nil:
+		return ""
+	case policy.IsLocked:
+		return "Locked"
+	}
+	return "Unlocked"
+}
+
+func objectRetentionMode(retention *storage.ObjectRetention) string {
+	if retention == nil {
+		return ""
+	}
+	return retention.Mode
+}
+
 func (b *BucketObject) GetOperationError(_ context.Context) error {
 	return nil
 }
 
+func (b *BucketObject) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	storageClient := client.(*storage.Client)
+
+	bucketObject := storageClient.Bucket(b.bucket).Object(b.name)
+	_, err := bucketObject.Generation(b.generation).Attrs(ctx)
+
+	return existence(err)
+}
+
 func (b *BucketObject) String() string {
 	return b.name
 }
@@ -0,0 +1,111 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
+	"google.golang.org/api/option"
+)
+
+// fakeStorage serves a single bucket, whose objects cannot be deleted as
+// long as it has a retention policy.
+type fakeStorage struct {
+	mu        sync.Mutex
+	retention bool
+	patches   int
+	deleted   []string
+}
+
+func (f *fakeStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPatch && r.URL.Path == "/storage/v1/b/data":
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if policy, ok := body["retentionPolicy"]; ok && policy == nil {
+			f.retention = false
+		}
+		f.patches = f.patches + 1
+		json.NewEncoder(w).Encode(map[string]string{"name": "data"})
+
+	case r.Method == http.MethodDelete && r.URL.Path == "/storage/v1/b/data/o/state":
+		if f.retention {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": map[string]interface{}{"code": 403, "message": "object is subject to bucket's retention policy"},
+			})
+			return
+		}
+		f.deleted = append(f.deleted, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, r.Method+" "+r.URL.Path, http.StatusNotImplemented)
+	}
+}
+
+func TestBucketObjectBucketRetention(t *testing.T) {
+	fake := &fakeStorage{retention: true}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	t.Setenv("STORAGE_EMULATOR_HOST", server.URL)
+
+	ctx := context.Background()
+	client, err := storage.NewClient(ctx, option.WithoutAuthentication())
+	if err != nil {
+		t.Fatal(err)
+	}
+	project := gcputil.NewProject(ctx, &gcputil.Credentials{Project: "retention-test"})
+
+	objects := []*BucketObject{
+		{name: "state", bucket: "data", generation: 1, bucketRetention: "Unlocked"},
+		{name: "state", bucket: "data", generation: 2, bucketRetention: "Unlocked"},
+	}
+
+	if err := objects[0].Remove(project, client); gcputil.ClassifyError(err) != gcputil.ErrorClassPermanent {
+		t.Fatalf("Expected the removal to be refused by the retention policy. Have: %v", err)
+	}
+
+	for _, object := range objects {
+		prepared, err := object.Prepare(project, client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !prepared {
+			t.Errorf("The object with the bucket retention policy was not prepared.")
+		}
+
+		if err := object.Remove(project, client); err != nil {
+			t.Fatalf("Removing the prepared object failed: %v", err)
+		}
+	}
+
+	if fake.retention {
+		t.Errorf("The retention policy of the bucket was not removed.")
+	}
+	if fake.patches != 1 {
+		t.Errorf("Wrong number of bucket updates. Want: 1. Have: %d", fake.patches)
+	}
+	if len(fake.deleted) != 2 {
+		t.Errorf("Wrong number of deleted objects. Want: 2. Have: %d", len(fake.deleted))
+	}
+}
+
+func TestBucketObjectLockedRetention(t *testing.T) {
+	project := gcputil.NewProject(context.Background(), &gcputil.Credentials{Project: "retention-test"})
+	object := &BucketObject{name: "audit-log", bucket: "data", retention: "Locked"}
+
+	// A locked retention never goes away, so retrying is pointless.
+	_, err := object.Prepare(project, nil)
+	if gcputil.ClassifyError(err) != gcputil.ErrorClassPermanent {
+		t.Errorf("Wrong class. Want: %s. Have: %s (%v)", gcputil.ErrorClassPermanent, gcputil.ClassifyError(err), err)
+	}
+}
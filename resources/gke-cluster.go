@@ -3,7 +3,6 @@ package resources
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	container "cloud.google.com/go/container/apiv1"
 	"cloud.google.com/go/container/apiv1/containerpb"
@@ -22,7 +21,10 @@ type GKECluster struct {
 }
 
 func init() {
-	register(ResourceTypeGKECluster, GetGKEClient, ListGKEClusters)
+	register(ResourceTypeGKECluster, GetGKEClient, ListGKEClusters,
+		withAPI(APIContainer),
+		withPrototype(&GKECluster{}),
+	)
 }
 
 func GetGKEClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -30,9 +32,13 @@ func GetGKEClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := container.NewClusterManagerClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APIContainer, gcputil.TransportGRPC)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create container client: %v", err)
+		return nil, err
+	}
+	client, err := container.NewClusterManagerClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create container client: %w", err)
 	}
 	project.AddClient(ResourceTypeGKECluster, client)
 	return client, nil
@@ -45,8 +51,8 @@ func ListGKEClusters(project *gcputil.Project, client gcputil.GCPClient) ([]Reso
 
 	var req *containerpb.ListClustersRequest
 	for _, location := range project.Locations {
-		// global isn't a valid location for GKE
-		if strings.ToLower(location) == "global" {
+		// only regions and zones are valid locations for GKE
+		if !gcputil.IsRegion(location) && !gcputil.IsZone(location) {
 			continue
 		}
 		req = &containerpb.ListClustersRequest{
@@ -55,7 +61,7 @@ func ListGKEClusters(project *gcputil.Project, client gcputil.GCPClient) ([]Reso
 
 		resp, err := gkeClient.ListClusters(project.GetContext(), req)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list GKE clusters: %v", err)
+			return nil, fmt.Errorf("failed to list GKE clusters: %w", err)
 		}
 
 		for _, cluster := range resp.Clusters {
@@ -93,6 +99,17 @@ func (x *GKECluster) GetOperationError(_ context.Context) error {
 	return nil
 }
 
+func (x *GKECluster) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	gkeClient := client.(*container.ClusterManagerClient)
+
+	req := &containerpb.GetClusterRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s/clusters/%s", project.Name, x.location, x.name),
+	}
+	_, err := gkeClient.GetCluster(ctx, req)
+
+	return existence(err)
+}
+
 func (x *GKECluster) String() string {
 	return x.name
 }
@@ -17,11 +17,14 @@ type GlobalNetworkEndpointGroup struct {
 	name         string
 	negType      string
 	creationDate string
-	operation    *compute.Operation
+	computeOperation
 }
 
 func init() {
-	register(ResourceTypeGlobalNetworkEndpointGroup, GetGlobalNetworkEndpointGroupClient, ListGlobalNetworkEndpointGroups)
+	register(ResourceTypeGlobalNetworkEndpointGroup, GetGlobalNetworkEndpointGroupClient, ListGlobalNetworkEndpointGroups,
+		withAPI(APICompute),
+		withPrototype(&GlobalNetworkEndpointGroup{}),
+	)
 }
 
 func GetGlobalNetworkEndpointGroupClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -29,9 +32,13 @@ func GetGlobalNetworkEndpointGroupClient(project *gcputil.Project) (gcputil.GCPC
 		return client, nil
 	}
 
-	client, err := compute.NewGlobalNetworkEndpointGroupsRESTClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APICompute, gcputil.TransportREST)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create global network endpoint group client: %v", err)
+		return nil, err
+	}
+	client, err := compute.NewGlobalNetworkEndpointGroupsRESTClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create global network endpoint group client: %w", err)
 	}
 	project.AddClient(ResourceTypeGlobalNetworkEndpointGroup, client)
 	return client, nil
@@ -54,7 +61,7 @@ func ListGlobalNetworkEndpointGroups(project *gcputil.Project, client gcputil.GC
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list global network endpiont groups: %v", err)
+			return nil, fmt.Errorf("failed to list global network endpiont groups: %w", err)
 		}
 
 		resources = append(resources, &GlobalNetworkEndpointGroup{
@@ -82,8 +89,16 @@ func (x *GlobalNetworkEndpointGroup) Remove(project *gcputil.Project, client gcp
 	return nil
 }
 
-func (x *GlobalNetworkEndpointGroup) GetOperationError(ctx context.Context) error {
-	return getComputeOperationError(ctx, x.operation)
+func (x *GlobalNetworkEndpointGroup) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	negClient := client.(*compute.GlobalNetworkEndpointGroupsClient)
+
+	req := &computepb.GetGlobalNetworkEndpointGroupRequest{
+		Project:              project.Name,
+		NetworkEndpointGroup: x.name,
+	}
+	_, err := negClient.Get(ctx, req)
+
+	return existence(err)
 }
 
 func (x *GlobalNetworkEndpointGroup) String() string {
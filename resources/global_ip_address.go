@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"strconv"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
@@ -18,11 +19,16 @@ type GlobalIPAddress struct {
 	name         string
 	network      string
 	creationDate string
-	operation    *compute.Operation
+	address      string
+	prefixLength string
+	computeOperation
 }
 
 func init() {
-	register(ResourceTypeGlobalIPAddress, GetGlobalIPAddressClient, ListGlobalIPAddresss)
+	register(ResourceTypeGlobalIPAddress, GetGlobalIPAddressClient, ListGlobalIPAddresss,
+		withAPI(APICompute),
+		withPrototype(&GlobalIPAddress{}),
+	)
 }
 
 func GetGlobalIPAddressClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -30,9 +36,13 @@ func GetGlobalIPAddressClient(project *gcputil.Project) (gcputil.GCPClient, erro
 		return client, nil
 	}
 
-	client, err := compute.NewGlobalAddressesRESTClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APICompute, gcputil.TransportREST)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create IP Global Addresses client: %v", err)
+		return nil, err
+	}
+	client, err := compute.NewGlobalAddressesRESTClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create IP Global Addresses client: %w", err)
 	}
 	project.AddClient(ResourceTypeGlobalIPAddress, client)
 	return client, nil
@@ -53,13 +63,15 @@ func ListGlobalIPAddresss(project *gcputil.Project, client gcputil.GCPClient) ([
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list global IP Addresses: %v", err)
+			return nil, fmt.Errorf("failed to list global IP Addresses: %w", err)
 		}
 
 		resources = append(resources, &GlobalIPAddress{
 			name:         *resp.Name,
 			network:      path.Base(UnPtrString(resp.Network, "")),
 			creationDate: *resp.CreationTimestamp,
+			address:      resp.GetAddress(),
+			prefixLength: prefixLength(resp.PrefixLength),
 		})
 	}
 	return resources, nil
@@ -81,8 +93,16 @@ func (x *GlobalIPAddress) Remove(project *gcputil.Project, client gcputil.GCPCli
 	return nil
 }
 
-func (x *GlobalIPAddress) GetOperationError(ctx context.Context) error {
-	return getComputeOperationError(ctx, x.operation)
+func (x *GlobalIPAddress) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	addressesClient := client.(*compute.GlobalAddressesClient)
+
+	req := &computepb.GetGlobalAddressRequest{
+		Project: project.Name,
+		Address: x.name,
+	}
+	_, err := addressesClient.Get(ctx, req)
+
+	return existence(err)
 }
 
 func (x *GlobalIPAddress) String() string {
@@ -94,6 +114,17 @@ func (x *GlobalIPAddress) Properties() types.Properties {
 	properties.Set("Name", x.name)
 	properties.Set("Network", x.network)
 	properties.Set("CreationDate", x.creationDate)
+	properties.Set("Address", x.address)
+	properties.Set("PrefixLength", x.prefixLength)
 
 	return properties
 }
+
+// prefixLength returns the prefix length of address ranges, which are
+// reserved for VPC peering. It is empty for single addresses.
+func prefixLength(length *int32) string {
+	if length == nil {
+		return ""
+	}
+	return strconv.Itoa(int(*length))
+}
@@ -19,7 +19,10 @@ type IAMRole struct {
 }
 
 func init() {
-	register(ResourceTypeIAMRole, GetIAMClient, ListIAMRoles)
+	register(ResourceTypeIAMRole, GetIAMClient, ListIAMRoles,
+		withAPI(APIIAM),
+		withPrototype(&IAMRole{}),
+	)
 }
 
 func ListIAMRoles(project *gcputil.Project, client gcputil.GCPClient) ([]Resource, error) {
@@ -69,6 +72,18 @@ func (x *IAMRole) GetOperationError(_ context.Context) error {
 	return nil
 }
 
+func (x *IAMRole) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	iamClient := client.(*gcputil.IAMClient)
+
+	// Deleted roles are kept for some days before they are gone.
+	role, err := iam.NewProjectsRolesService(iamClient.GetIAMService()).Get(x.id).Context(ctx).Do()
+	if err != nil {
+		return existence(err)
+	}
+
+	return !role.Deleted, nil
+}
+
 func (x *IAMRole) String() string {
 	return x.name
 }
@@ -20,7 +20,10 @@ type IAMServiceAccount struct {
 }
 
 func init() {
-	register(ResourceTypeIAMServiceAccount, GetIAMClient, ListIAMServiceAccounts)
+	register(ResourceTypeIAMServiceAccount, GetIAMClient, ListIAMServiceAccounts,
+		withAPI(APIIAM),
+		withPrototype(&IAMServiceAccount{}),
+	)
 }
 
 func GetIAMClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -28,9 +31,13 @@ func GetIAMClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := gcputil.NewIAMClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APIIAM, gcputil.TransportREST)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create IAM client: %v", err)
+		return nil, err
+	}
+	client, err := gcputil.NewIAMClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create IAM client: %w", err)
 	}
 	project.AddClient(ResourceTypeIAMServiceAccount, client)
 	return client, nil
@@ -81,6 +88,14 @@ func (x *IAMServiceAccount) GetOperationError(_ context.Context) error {
 	return nil
 }
 
+func (x *IAMServiceAccount) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	iamClient := client.(*gcputil.IAMClient)
+
+	_, err := iam.NewProjectsServiceAccountsService(iamClient.GetIAMService()).Get(x.id).Context(ctx).Do()
+
+	return existence(err)
+}
+
 func (x *IAMServiceAccount) String() string {
 	return x.name
 }
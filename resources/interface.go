@@ -3,6 +3,8 @@ package resources
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/dshelley66/gcp-nuke/pkg/config"
 	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
@@ -14,8 +16,15 @@ type ResourceMethods map[string]ResourceMethod
 type ResourceMethod struct {
 	Lister       ResourceLister
 	ClientGetter ResourceClientGetter
+	API          string
+	DependsOn    []string
+	Prototype    Resource
+	Aliases      []string
 }
 
+// RegisterOption customizes a resource type while it gets registered.
+type RegisterOption func(name string, method *ResourceMethod)
+
 type ResourceLister func(*gcputil.Project, gcputil.GCPClient) ([]Resource, error)
 type ResourceClientGetter func(*gcputil.Project) (gcputil.GCPClient, error)
 
@@ -39,6 +48,30 @@ type ResourcePropertyGetter interface {
 	Properties() types.Properties
 }
 
+// Getter is implemented by resources which can check their existence with a
+// single request. Otherwise the whole resource type is listed again, while
+// waiting for the removal.
+type Getter interface {
+	Resource
+	Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error)
+}
+
+// OperationResumer is implemented by resources whose removal returns a long
+// running operation, which can be picked up again by its name in a later run.
+type OperationResumer interface {
+	Resource
+	OperationName() string
+	ResumeOperation(project *gcputil.Project, client gcputil.GCPClient, name string) error
+}
+
+// Preparer is implemented by resources which need a mutation, like disabling
+// their deletion protection, before they can be removed. Prepare returns
+// whether anything was changed.
+type Preparer interface {
+	Resource
+	Prepare(project *gcputil.Project, client gcputil.GCPClient) (bool, error)
+}
+
 type FeatureFlagGetter interface {
 	Resource
 	FeatureFlags(config.FeatureFlags)
@@ -46,17 +79,71 @@ type FeatureFlagGetter interface {
 
 var resourceMethods = make(ResourceMethods)
 
-func register(name string, clientGetter ResourceClientGetter, lister ResourceLister) {
+// resourceAliases maps former names of resource types to their current
+// names.
+var resourceAliases = make(map[string]string)
+
+func register(name string, clientGetter ResourceClientGetter, lister ResourceLister, opts ...RegisterOption) {
 	_, exists := resourceMethods[name]
 	if exists {
 		panic(fmt.Sprintf("a resource with the name %s already exists", name))
 	}
+	_, exists = resourceAliases[name]
+	if exists {
+		panic(fmt.Sprintf("the name %s is already used as alias", name))
+	}
 
-	resourceMethods[name] = ResourceMethod{
+	method := ResourceMethod{
 		ClientGetter: clientGetter,
 		Lister:       lister,
 	}
+	for _, opt := range opts {
+		opt(name, &method)
+	}
+
+	resourceMethods[name] = method
+}
+
+// withAPI declares the Google Cloud API which is used to manage resources of
+// the registered type.
+func withAPI(api string) RegisterOption {
+	return func(name string, method *ResourceMethod) {
+		method.API = api
+	}
+}
+
+// dependsOn declares resource types which have to be removed before
+// resources of the registered type can be removed. For example a VPC cannot
+// be deleted as long as it still contains subnets.
+func dependsOn(resourceTypes ...string) RegisterOption {
+	return func(name string, method *ResourceMethod) {
+		method.DependsOn = append(method.DependsOn, resourceTypes...)
+	}
+}
+
+// withPrototype registers an empty resource of the type, which is used to
+// inspect the type without listing it, eg for the properties it emits.
+func withPrototype(prototype Resource) RegisterOption {
+	return func(name string, method *ResourceMethod) {
+		method.Prototype = prototype
+	}
+}
 
+// withAliases registers former names of the resource type, eg after it got
+// renamed or split. The aliases keep working in the config, but are reported
+// as deprecated.
+func withAliases(aliases ...string) RegisterOption {
+	return func(name string, method *ResourceMethod) {
+		for _, alias := range aliases {
+			_, exists := resourceMethods[alias]
+			_, aliased := resourceAliases[alias]
+			if exists || aliased || alias == name {
+				panic(fmt.Sprintf("the alias %s of %s is already used", alias, name))
+			}
+			resourceAliases[alias] = name
+		}
+		method.Aliases = append(method.Aliases, aliases...)
+	}
 }
 
 func GetLister(name string) ResourceLister {
@@ -67,6 +154,106 @@ func GetClient(name string) ResourceClientGetter {
 	return resourceMethods[name].ClientGetter
 }
 
+// GetAPI returns the name of the Google Cloud API which is used to manage
+// resources of the given type.
+func GetAPI(name string) string {
+	return resourceMethods[name].API
+}
+
+// GetDependencies returns the resource types which have to be removed before
+// resources of the given type.
+func GetDependencies(name string) []string {
+	return resourceMethods[name].DependsOn
+}
+
+// GetPrototype returns an empty resource of the given type. It must not be
+// used to make any requests.
+func GetPrototype(name string) Resource {
+	return resourceMethods[name].Prototype
+}
+
+// GetAliases returns the former names of the given resource type.
+func GetAliases(name string) []string {
+	return resourceMethods[name].Aliases
+}
+
+// GetAliasMapping returns the current names of all resource types by their
+// former names.
+func GetAliasMapping() map[string]string {
+	mapping := make(map[string]string, len(resourceAliases))
+	for alias, name := range resourceAliases {
+		mapping[alias] = name
+	}
+	return mapping
+}
+
+// CheckDependencies checks that the registered resource types only depend on
+// registered types and that there are no cycles, which would block their
+// removal forever.
+func CheckDependencies() error {
+	return resourceMethods.CheckDependencies()
+}
+
+// CheckDependencies checks the dependencies of the resource types. It fails
+// on unknown names and on cycles.
+func (m ResourceMethods) CheckDependencies() error {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, dependency := range m[name].DependsOn {
+			if _, ok := m[dependency]; !ok {
+				return fmt.Errorf("the resource type %s depends on the unknown resource type %s", name, dependency)
+			}
+		}
+	}
+
+	// Depth-first search, which reports the first cycle it finds.
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	states := map[string]int{}
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch states[name] {
+		case visited:
+			return nil
+		case visiting:
+			start := 0
+			for path[start] != name {
+				start++
+			}
+			cycle := append(append([]string{}, path[start:]...), name)
+			return fmt.Errorf("the resource types have a dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+
+		states[name] = visiting
+		path = append(path, name)
+		for _, dependency := range m[name].DependsOn {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		states[name] = visited
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func GetListerNames() []string {
 	names := []string{}
 	for resourceType := range resourceMethods {
@@ -0,0 +1,53 @@
+package resources
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithAliases(t *testing.T) {
+	const name = "TestRenamedResource"
+
+	register(name, nil, nil, withAliases("TestFormerResource", "TestOldResource"))
+	t.Cleanup(func() {
+		delete(resourceMethods, name)
+		delete(resourceAliases, "TestFormerResource")
+		delete(resourceAliases, "TestOldResource")
+	})
+
+	want := []string{"TestFormerResource", "TestOldResource"}
+	if have := GetAliases(name); !reflect.DeepEqual(have, want) {
+		t.Errorf("Wrong aliases. Want: %v. Have: %v", want, have)
+	}
+
+	mapping := GetAliasMapping()
+	for _, alias := range want {
+		if mapping[alias] != name {
+			t.Errorf("Wrong type of the alias %s. Want: %s. Have: %s", alias, name, mapping[alias])
+		}
+	}
+
+	// Aliases must neither shadow a type nor another alias.
+	for _, alias := range []string{ResourceTypeVPC, "TestOldResource", "TestRenamedResource"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected a panic for the alias %s.", alias)
+				}
+			}()
+			register("TestOtherResource", nil, nil, withAliases(alias))
+		}()
+	}
+	if _, ok := resourceMethods["TestOtherResource"]; ok {
+		t.Errorf("A type with an invalid alias was registered.")
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Expected a panic for a type named like an alias.")
+			}
+		}()
+		register("TestFormerResource", nil, nil)
+	}()
+}
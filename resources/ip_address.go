@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"path"
-	"strings"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
@@ -20,11 +19,16 @@ type IPAddress struct {
 	network      string
 	creationDate string
 	region       string
-	operation    *compute.Operation
+	address      string
+	computeOperation
 }
 
 func init() {
-	register(ResourceTypeIPAddress, GetIPAddressClient, ListIPAddresss)
+	register(ResourceTypeIPAddress, GetIPAddressClient, ListIPAddresss,
+		withAPI(APICompute),
+		dependsOn(ResourceTypeComputeInstance),
+		withPrototype(&IPAddress{}),
+	)
 }
 
 func GetIPAddressClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -32,9 +36,13 @@ func GetIPAddressClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := compute.NewAddressesRESTClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APICompute, gcputil.TransportREST)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create IP Addresses client: %v", err)
+		return nil, err
+	}
+	client, err := compute.NewAddressesRESTClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create IP Addresses client: %w", err)
 	}
 	project.AddClient(ResourceTypeIPAddress, client)
 	return client, nil
@@ -46,8 +54,8 @@ func ListIPAddresss(project *gcputil.Project, client gcputil.GCPClient) ([]Resou
 	resources := make([]Resource, 0)
 	var req *computepb.ListAddressesRequest
 	for _, location := range project.Locations {
-		// global isn't a valid location for IPAddress
-		if strings.ToLower(location) == "global" {
+		// only regions are valid locations for IPAddress
+		if !gcputil.IsRegion(location) {
 			continue
 		}
 		req = &computepb.ListAddressesRequest{
@@ -62,7 +70,7 @@ func ListIPAddresss(project *gcputil.Project, client gcputil.GCPClient) ([]Resou
 			}
 
 			if err != nil {
-				return nil, fmt.Errorf("failed to list IP Addresses: %v", err)
+				return nil, fmt.Errorf("failed to list IP Addresses: %w", err)
 			}
 
 			resources = append(resources, &IPAddress{
@@ -70,6 +78,7 @@ func ListIPAddresss(project *gcputil.Project, client gcputil.GCPClient) ([]Resou
 				network:      path.Base(UnPtrString(resp.Network, "")),
 				creationDate: *resp.CreationTimestamp,
 				region:       path.Base(UnPtrString(resp.Region, "")),
+				address:      resp.GetAddress(),
 			})
 		}
 	}
@@ -93,8 +102,17 @@ func (x *IPAddress) Remove(project *gcputil.Project, client gcputil.GCPClient) (
 	return nil
 }
 
-func (x *IPAddress) GetOperationError(ctx context.Context) error {
-	return getComputeOperationError(ctx, x.operation)
+func (x *IPAddress) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	addressesClient := client.(*compute.AddressesClient)
+
+	req := &computepb.GetAddressRequest{
+		Project: project.Name,
+		Address: x.name,
+		Region:  x.region,
+	}
+	_, err := addressesClient.Get(ctx, req)
+
+	return existence(err)
 }
 
 func (x *IPAddress) String() string {
@@ -106,6 +124,7 @@ func (x *IPAddress) Properties() types.Properties {
 	properties.Set("Name", x.name)
 	properties.Set("Network", x.network)
 	properties.Set("CreationDate", x.creationDate)
+	properties.Set("Address", x.address)
 
 	return properties
 }
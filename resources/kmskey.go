@@ -22,7 +22,10 @@ type KmsKey struct {
 }
 
 func init() {
-	register(ResourceTypeKmsKey, GetKMSClient, ListKmsKeys)
+	register(ResourceTypeKmsKey, GetKMSClient, ListKmsKeys,
+		withAPI(APICloudKMS),
+		withPrototype(&KmsKey{}),
+	)
 }
 
 func GetKMSClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -30,14 +33,35 @@ func GetKMSClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := kms.NewKeyManagementClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APICloudKMS, gcputil.TransportGRPC)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create KMS client: %v", err)
+		return nil, err
+	}
+	client, err := kms.NewKeyManagementClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create KMS client: %w", err)
 	}
 	project.AddClient(ResourceTypeKmsKey, client)
 	return client, nil
 }
 
+// kmsMultiRegions maps the multi-regions to their names in Cloud KMS.
+var kmsMultiRegions = map[string]string{
+	"asia": "asia",
+	"eu":   "europe",
+	"us":   "us",
+}
+
+// kmsLocation returns the name of the location in Cloud KMS. Key rings can be
+// global, regional or in a multi-region, but not in a zone.
+func kmsLocation(location string) (string, bool) {
+	if location == gcputil.LocationGlobal || gcputil.IsRegion(location) {
+		return location, true
+	}
+	name, ok := kmsMultiRegions[location]
+	return name, ok
+}
+
 func ListKmsKeys(project *gcputil.Project, client gcputil.GCPClient) ([]Resource, error) {
 	kmsClient := client.(*kms.KeyManagementClient)
 
@@ -45,6 +69,10 @@ func ListKmsKeys(project *gcputil.Project, client gcputil.GCPClient) ([]Resource
 
 	var reqKeyRing *kmspb.ListKeyRingsRequest
 	for _, location := range project.Locations {
+		location, ok := kmsLocation(location)
+		if !ok {
+			continue
+		}
 		reqKeyRing = &kmspb.ListKeyRingsRequest{
 			Parent: fmt.Sprintf("projects/%s/locations/%s", project.Name, location),
 		}
@@ -55,7 +83,7 @@ func ListKmsKeys(project *gcputil.Project, client gcputil.GCPClient) ([]Resource
 				break
 			}
 			if err != nil {
-				return nil, fmt.Errorf("failed to list key rings for location %s: %v", location, err)
+				return nil, fmt.Errorf("failed to list key rings for location %s: %w", location, err)
 			}
 			reqKey := &kmspb.ListCryptoKeysRequest{
 				Parent: keyRing.Name,
@@ -67,7 +95,7 @@ func ListKmsKeys(project *gcputil.Project, client gcputil.GCPClient) ([]Resource
 					break
 				}
 				if err != nil {
-					return nil, fmt.Errorf("failed to list key for keyring %s: %v", keyRing.Name, err)
+					return nil, fmt.Errorf("failed to list key for keyring %s: %w", keyRing.Name, err)
 				}
 				if isDestroyed(key.Primary) {
 					continue
@@ -98,7 +126,7 @@ func (x *KmsKey) Remove(project *gcputil.Project, client gcputil.GCPClient) erro
 			break
 		}
 		if err != nil {
-			return fmt.Errorf("failed to list key versions for key %s: %v", x.name, err)
+			return fmt.Errorf("failed to list key versions for key %s: %w", x.name, err)
 		}
 
 		if !isDestroyed(keyVersion) {
@@ -125,6 +153,22 @@ func isDestroyed(keyVersion *kmspb.CryptoKeyVersion) bool {
 	return keyVersion.State == kmspb.CryptoKeyVersion_DESTROYED || keyVersion.State == kmspb.CryptoKeyVersion_DESTROY_SCHEDULED
 }
 
+func (x *KmsKey) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	kmsClient := client.(*kms.KeyManagementClient)
+
+	// Keys cannot be deleted. They are gone for gcp-nuke, once the primary
+	// version is destroyed.
+	req := &kmspb.GetCryptoKeyRequest{
+		Name: x.name,
+	}
+	key, err := kmsClient.GetCryptoKey(ctx, req)
+	if err != nil {
+		return existence(err)
+	}
+
+	return !isDestroyed(key.Primary), nil
+}
+
 func (x *KmsKey) String() string {
 	return x.name
 }
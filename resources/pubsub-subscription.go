@@ -19,7 +19,10 @@ type PubSubSubscription struct {
 }
 
 func init() {
-	register(ResourceTypePubSubSubscription, GetPubSubClient, ListPubSubSubscriptions)
+	register(ResourceTypePubSubSubscription, GetPubSubClient, ListPubSubSubscriptions,
+		withAPI(APIPubSub),
+		withPrototype(&PubSubSubscription{}),
+	)
 }
 
 func ListPubSubSubscriptions(project *gcputil.Project, client gcputil.GCPClient) ([]Resource, error) {
@@ -35,7 +38,7 @@ func ListPubSubSubscriptions(project *gcputil.Project, client gcputil.GCPClient)
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list pubsub subscriptions: %v", err)
+			return nil, fmt.Errorf("failed to list pubsub subscriptions: %w", err)
 		}
 		// get the SubscriptionConfig
 		sc, err := subscription.Config(project.GetContext())
@@ -62,6 +65,12 @@ func (x *PubSubSubscription) GetOperationError(_ context.Context) error {
 	return nil
 }
 
+func (x *PubSubSubscription) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	pubsubClient := client.(*pubsub.Client)
+
+	return pubsubClient.Subscription(x.name).Exists(ctx)
+}
+
 func (x *PubSubSubscription) String() string {
 	return x.name
 }
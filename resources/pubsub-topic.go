@@ -19,7 +19,10 @@ type PubSubTopic struct {
 }
 
 func init() {
-	register(ResourceTypePubSubTopic, GetPubSubClient, ListPubSubTopics)
+	register(ResourceTypePubSubTopic, GetPubSubClient, ListPubSubTopics,
+		withAPI(APIPubSub),
+		withPrototype(&PubSubTopic{}),
+	)
 }
 
 func GetPubSubClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -27,9 +30,13 @@ func GetPubSubClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := pubsub.NewClient(project.GetContext(), project.Name, project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APIPubSub, gcputil.TransportGRPC)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create pubsub client: %v", err)
+		return nil, err
+	}
+	client, err := pubsub.NewClient(project.GetContext(), project.Name, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pubsub client: %w", err)
 	}
 	project.AddClient(ResourceTypePubSubTopic, client)
 	return client, nil
@@ -48,7 +55,7 @@ func ListPubSubTopics(project *gcputil.Project, client gcputil.GCPClient) ([]Res
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list pubsub topics: %v", err)
+			return nil, fmt.Errorf("failed to list pubsub topics: %w", err)
 		}
 		// get the TopicConfig
 		tc, err := topic.Config(project.GetContext())
@@ -75,6 +82,12 @@ func (x *PubSubTopic) GetOperationError(_ context.Context) error {
 	return nil
 }
 
+func (x *PubSubTopic) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	pubsubClient := client.(*pubsub.Client)
+
+	return pubsubClient.Topic(x.name).Exists(ctx)
+}
+
 func (x *PubSubTopic) String() string {
 	return x.name
 }
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"path"
-	"strings"
 	"time"
 
 	redis "cloud.google.com/go/redis/apiv1"
@@ -27,7 +26,10 @@ type Redis struct {
 }
 
 func init() {
-	register(ResourceTypeRedis, GetRedisClient, ListRedis)
+	register(ResourceTypeRedis, GetRedisClient, ListRedis,
+		withAPI(APIRedis),
+		withPrototype(&Redis{}),
+	)
 }
 
 func GetRedisClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -35,9 +37,13 @@ func GetRedisClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := redis.NewCloudRedisClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APIRedis, gcputil.TransportGRPC)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Redis client: %v", err)
+		return nil, err
+	}
+	client, err := redis.NewCloudRedisClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Redis client: %w", err)
 	}
 	project.AddClient(ResourceTypeRedis, client)
 	return client, nil
@@ -50,8 +56,8 @@ func ListRedis(project *gcputil.Project, client gcputil.GCPClient) ([]Resource,
 
 	var reqRedis *redispb.ListInstancesRequest
 	for _, location := range project.Locations {
-		// global isn't a valid location for Redis
-		if strings.ToLower(location) == "global" {
+		// only regions are valid locations for Redis
+		if !gcputil.IsRegion(location) {
 			continue
 		}
 
@@ -65,7 +71,7 @@ func ListRedis(project *gcputil.Project, client gcputil.GCPClient) ([]Resource,
 				break
 			}
 			if err != nil {
-				return nil, fmt.Errorf("failed to list Redis instances for location %s: %v", location, err)
+				return nil, fmt.Errorf("failed to list Redis instances for location %s: %w", location, err)
 			}
 			resources = append(resources, &Redis{
 				name:         path.Base(resp.GetName()),
@@ -102,6 +108,29 @@ func (x *Redis) GetOperationError(ctx context.Context) (err error) {
 	return err
 }
 
+func (x *Redis) OperationName() string {
+	if x.operation == nil {
+		return ""
+	}
+	return x.operation.Name()
+}
+
+func (x *Redis) ResumeOperation(project *gcputil.Project, client gcputil.GCPClient, name string) error {
+	x.operation = client.(*redis.CloudRedisClient).DeleteInstanceOperation(name)
+	return nil
+}
+
+func (x *Redis) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	redisClient := client.(*redis.CloudRedisClient)
+
+	req := &redispb.GetInstanceRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s/instances/%s", project.Name, x.region, x.name),
+	}
+	_, err := redisClient.GetInstance(ctx, req)
+
+	return existence(err)
+}
+
 func (x *Redis) String() string {
 	return x.name
 }
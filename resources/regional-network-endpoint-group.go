@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"path"
-	"strings"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
@@ -20,11 +19,14 @@ type RegionalNetworkEndpointGroup struct {
 	negType      string
 	region       string
 	creationDate string
-	operation    *compute.Operation
+	computeOperation
 }
 
 func init() {
-	register(ResourceTypeRegionalNetworkEndpointGroup, GetRegionalNetworkEndpointGroupClient, ListRegionalNetworkEndpointGroups)
+	register(ResourceTypeRegionalNetworkEndpointGroup, GetRegionalNetworkEndpointGroupClient, ListRegionalNetworkEndpointGroups,
+		withAPI(APICompute),
+		withPrototype(&RegionalNetworkEndpointGroup{}),
+	)
 }
 
 func GetRegionalNetworkEndpointGroupClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -32,9 +34,13 @@ func GetRegionalNetworkEndpointGroupClient(project *gcputil.Project) (gcputil.GC
 		return client, nil
 	}
 
-	client, err := compute.NewRegionNetworkEndpointGroupsRESTClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APICompute, gcputil.TransportREST)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create regional network endpoint group client: %v", err)
+		return nil, err
+	}
+	client, err := compute.NewRegionNetworkEndpointGroupsRESTClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create regional network endpoint group client: %w", err)
 	}
 	project.AddClient(ResourceTypeRegionalNetworkEndpointGroup, client)
 	return client, nil
@@ -46,8 +52,8 @@ func ListRegionalNetworkEndpointGroups(project *gcputil.Project, client gcputil.
 	resources := make([]Resource, 0)
 	var req *computepb.ListRegionNetworkEndpointGroupsRequest
 	for _, location := range project.Locations {
-		// global isn't a valid location for regional NEGs
-		if strings.ToLower(location) == "global" {
+		// only regions are valid locations for regional NEGs
+		if !gcputil.IsRegion(location) {
 			continue
 		}
 
@@ -64,7 +70,7 @@ func ListRegionalNetworkEndpointGroups(project *gcputil.Project, client gcputil.
 			}
 
 			if err != nil {
-				return nil, fmt.Errorf("failed to list Regional network endpiont groups: %v", err)
+				return nil, fmt.Errorf("failed to list Regional network endpiont groups: %w", err)
 			}
 
 			resources = append(resources, &RegionalNetworkEndpointGroup{
@@ -95,8 +101,17 @@ func (x *RegionalNetworkEndpointGroup) Remove(project *gcputil.Project, client g
 	return nil
 }
 
-func (x *RegionalNetworkEndpointGroup) GetOperationError(ctx context.Context) error {
-	return getComputeOperationError(ctx, x.operation)
+func (x *RegionalNetworkEndpointGroup) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	negClient := client.(*compute.RegionNetworkEndpointGroupsClient)
+
+	req := &computepb.GetRegionNetworkEndpointGroupRequest{
+		Project:              project.Name,
+		Region:               x.region,
+		NetworkEndpointGroup: x.name,
+	}
+	_, err := negClient.Get(ctx, req)
+
+	return existence(err)
 }
 
 func (x *RegionalNetworkEndpointGroup) String() string {
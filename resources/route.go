@@ -18,11 +18,16 @@ type Route struct {
 	name         string
 	network      string
 	creationDate string
-	operation    *compute.Operation
+	destRange    string
+	priority     uint32
+	computeOperation
 }
 
 func init() {
-	register(ResourceTypeRoute, GetRouteClient, ListRoutes)
+	register(ResourceTypeRoute, GetRouteClient, ListRoutes,
+		withAPI(APICompute),
+		withPrototype(&Route{}),
+	)
 }
 
 func GetRouteClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -30,9 +35,13 @@ func GetRouteClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := compute.NewRoutesRESTClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APICompute, gcputil.TransportREST)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create routes client: %v", err)
+		return nil, err
+	}
+	client, err := compute.NewRoutesRESTClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create routes client: %w", err)
 	}
 	project.AddClient(ResourceTypeRoute, client)
 	return client, nil
@@ -55,12 +64,14 @@ func ListRoutes(project *gcputil.Project, client gcputil.GCPClient) ([]Resource,
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list routes: %v", err)
+			return nil, fmt.Errorf("failed to list routes: %w", err)
 		}
 		resources = append(resources, &Route{
 			name:         *resp.Name,
 			network:      *resp.Network,
 			creationDate: *resp.CreationTimestamp,
+			destRange:    resp.GetDestRange(),
+			priority:     resp.GetPriority(),
 		})
 	}
 	return resources, nil
@@ -82,8 +93,16 @@ func (x *Route) Remove(project *gcputil.Project, client gcputil.GCPClient) (err
 	return nil
 }
 
-func (x *Route) GetOperationError(ctx context.Context) error {
-	return getComputeOperationError(ctx, x.operation)
+func (x *Route) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	routesClient := client.(*compute.RoutesClient)
+
+	req := &computepb.GetRouteRequest{
+		Route:   x.name,
+		Project: project.Name,
+	}
+	_, err := routesClient.Get(ctx, req)
+
+	return existence(err)
 }
 
 func (x *Route) String() string {
@@ -95,6 +114,8 @@ func (x *Route) Properties() types.Properties {
 	properties.Set("Name", x.name)
 	properties.Set("Network", path.Base(x.network))
 	properties.Set("CreationDate", x.creationDate)
+	properties.Set("DestRange", x.destRange)
+	properties.Set("Priority", x.priority)
 
 	return properties
 }
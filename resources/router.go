@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"path"
-	"strings"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
@@ -20,11 +19,14 @@ type Router struct {
 	network      string
 	creationDate string
 	region       string
-	operation    *compute.Operation
+	computeOperation
 }
 
 func init() {
-	register(ResourceTypeRouter, GetRouterClient, ListRouters)
+	register(ResourceTypeRouter, GetRouterClient, ListRouters,
+		withAPI(APICompute),
+		withPrototype(&Router{}),
+	)
 }
 
 func GetRouterClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -32,9 +34,13 @@ func GetRouterClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := compute.NewRoutersRESTClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APICompute, gcputil.TransportREST)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create routers client: %v", err)
+		return nil, err
+	}
+	client, err := compute.NewRoutersRESTClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create routers client: %w", err)
 	}
 	project.AddClient(ResourceTypeRouter, client)
 	return client, nil
@@ -46,8 +52,8 @@ func ListRouters(project *gcputil.Project, client gcputil.GCPClient) ([]Resource
 	resources := make([]Resource, 0)
 	var req *computepb.ListRoutersRequest
 	for _, location := range project.Locations {
-		// global isn't a valid location for routers
-		if strings.ToLower(location) == "global" {
+		// only regions are valid locations for routers
+		if !gcputil.IsRegion(location) {
 			continue
 		}
 		req = &computepb.ListRoutersRequest{
@@ -63,7 +69,7 @@ func ListRouters(project *gcputil.Project, client gcputil.GCPClient) ([]Resource
 			}
 
 			if err != nil {
-				return nil, fmt.Errorf("failed to list routers: %v", err)
+				return nil, fmt.Errorf("failed to list routers: %w", err)
 			}
 			resources = append(resources, &Router{
 				name:         *resp.Name,
@@ -85,7 +91,8 @@ func (x *Router) Remove(project *gcputil.Project, client gcputil.GCPClient) erro
 		Router:  x.name,
 	}
 
-	_, err := routersClient.Delete(project.GetContext(), req)
+	var err error
+	x.operation, err = routersClient.Delete(project.GetContext(), req)
 	if err != nil {
 		return err
 	}
@@ -93,8 +100,17 @@ func (x *Router) Remove(project *gcputil.Project, client gcputil.GCPClient) erro
 	return nil
 }
 
-func (x *Router) GetOperationError(ctx context.Context) error {
-	return getComputeOperationError(ctx, x.operation)
+func (x *Router) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	routersClient := client.(*compute.RoutersClient)
+
+	req := &computepb.GetRouterRequest{
+		Project: project.Name,
+		Region:  x.region,
+		Router:  x.name,
+	}
+	_, err := routersClient.Get(ctx, req)
+
+	return existence(err)
 }
 
 func (x *Router) String() string {
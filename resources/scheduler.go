@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"path"
-	"strings"
 
 	scheduler "cloud.google.com/go/scheduler/apiv1"
 	"cloud.google.com/go/scheduler/apiv1/schedulerpb"
@@ -23,7 +22,10 @@ type SchedulerJob struct {
 }
 
 func init() {
-	register(ResourceTypeSchedulerJob, GetSchedulerClient, ListSchedulerJobs)
+	register(ResourceTypeSchedulerJob, GetSchedulerClient, ListSchedulerJobs,
+		withAPI(APICloudScheduler),
+		withPrototype(&SchedulerJob{}),
+	)
 }
 
 func GetSchedulerClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -31,9 +33,13 @@ func GetSchedulerClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := scheduler.NewCloudSchedulerClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APICloudScheduler, gcputil.TransportGRPC)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create scheduler client: %v", err)
+		return nil, err
+	}
+	client, err := scheduler.NewCloudSchedulerClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scheduler client: %w", err)
 	}
 	project.AddClient(ResourceTypeSchedulerJob, client)
 	return client, nil
@@ -45,8 +51,8 @@ func ListSchedulerJobs(project *gcputil.Project, client gcputil.GCPClient) ([]Re
 	resources := make([]Resource, 0)
 
 	for _, location := range project.Locations {
-		// global isn't a valid location for Scheduler
-		if strings.ToLower(location) == "global" {
+		// only regions are valid locations for Scheduler
+		if !gcputil.IsRegion(location) {
 			continue
 		}
 		req := &schedulerpb.ListJobsRequest{
@@ -60,7 +66,7 @@ func ListSchedulerJobs(project *gcputil.Project, client gcputil.GCPClient) ([]Re
 			}
 
 			if err != nil {
-				return nil, fmt.Errorf("failed to list scheduler jobs: %v", err)
+				return nil, fmt.Errorf("failed to list scheduler jobs: %w", err)
 			}
 			resources = append(resources, &SchedulerJob{
 				name:     path.Base(job.Name),
@@ -90,6 +96,17 @@ func (x *SchedulerJob) GetOperationError(_ context.Context) error {
 	return nil
 }
 
+func (x *SchedulerJob) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	schedulerClient := client.(*scheduler.CloudSchedulerClient)
+
+	req := &schedulerpb.GetJobRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s/jobs/%s", x.project, x.location, x.name),
+	}
+	_, err := schedulerClient.GetJob(ctx, req)
+
+	return existence(err)
+}
+
 func (x *SchedulerJob) String() string {
 	return x.name
 }
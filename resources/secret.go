@@ -21,7 +21,10 @@ type Secret struct {
 }
 
 func init() {
-	register(ResourceTypeSecret, GetSecretClient, ListSecret)
+	register(ResourceTypeSecret, GetSecretClient, ListSecret,
+		withAPI(APISecretManager),
+		withPrototype(&Secret{}),
+	)
 }
 
 func GetSecretClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -29,9 +32,13 @@ func GetSecretClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := secretmanager.NewClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APISecretManager, gcputil.TransportGRPC)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create secretmanager client: %v", err)
+		return nil, err
+	}
+	client, err := secretmanager.NewClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secretmanager client: %w", err)
 	}
 	project.AddClient(ResourceTypeSecret, client)
 	return client, nil
@@ -53,7 +60,7 @@ func ListSecret(project *gcputil.Project, client gcputil.GCPClient) ([]Resource,
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list secrets: %v", err)
+			return nil, fmt.Errorf("failed to list secrets: %w", err)
 		}
 		resources = append(resources, &Secret{
 			name:         resp.Name,
@@ -83,6 +90,17 @@ func (x *Secret) GetOperationError(_ context.Context) error {
 	return nil
 }
 
+func (x *Secret) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	secretClient := client.(*secretmanager.Client)
+
+	req := &secretmanagerpb.GetSecretRequest{
+		Name: x.name,
+	}
+	_, err := secretClient.GetSecret(ctx, req)
+
+	return existence(err)
+}
+
 func (x *Secret) String() string {
 	return x.name
 }
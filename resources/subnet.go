@@ -16,15 +16,28 @@ import (
 const ResourceTypeSubnet = "Subnet"
 
 type Subnet struct {
-	name         string
-	network      string
-	creationDate string
-	region       string
-	operation    *compute.Operation
+	name            string
+	network         string
+	creationDate    string
+	region          string
+	ipCIDRRange     string
+	secondaryRanges []string
+	computeOperation
 }
 
 func init() {
-	register(ResourceTypeSubnet, GetSubnetworkClient, ListSubnets)
+	register(ResourceTypeSubnet, GetSubnetworkClient, ListSubnets,
+		withAPI(APICompute),
+		dependsOn(
+			ResourceTypeComputeInstance,
+			ResourceTypeGKECluster,
+			ResourceTypeIPAddress,
+			ResourceTypeVpcAccess,
+			ResourceTypeRegionalNetworkEndpointGroup,
+			ResourceTypeZonalNetworkEndpointGroup,
+		),
+		withPrototype(&Subnet{}),
+	)
 }
 
 func GetSubnetworkClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -32,9 +45,13 @@ func GetSubnetworkClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := compute.NewSubnetworksRESTClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APICompute, gcputil.TransportREST)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create subnetwork client: %v", err)
+		return nil, err
+	}
+	client, err := compute.NewSubnetworksRESTClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subnetwork client: %w", err)
 	}
 	project.AddClient(ResourceTypeSubnet, client)
 	return client, nil
@@ -46,8 +63,8 @@ func ListSubnets(project *gcputil.Project, client gcputil.GCPClient) ([]Resource
 	resources := make([]Resource, 0)
 	var req *computepb.ListSubnetworksRequest
 	for _, location := range project.Locations {
-		// global isn't a valid location for subnets
-		if strings.ToLower(location) == "global" {
+		// only regions are valid locations for subnets
+		if !gcputil.IsRegion(location) {
 			continue
 		}
 		req = &computepb.ListSubnetworksRequest{
@@ -64,14 +81,19 @@ func ListSubnets(project *gcputil.Project, client gcputil.GCPClient) ([]Resource
 			}
 
 			if err != nil {
-				return nil, fmt.Errorf("failed to list subnetworks: %v", err)
+				return nil, fmt.Errorf("failed to list subnetworks: %w", err)
 			}
-			resources = append(resources, &Subnet{
+			subnet := &Subnet{
 				name:         *resp.Name,
 				network:      path.Base(*resp.Network),
 				creationDate: *resp.CreationTimestamp,
 				region:       path.Base(*resp.Region),
-			})
+				ipCIDRRange:  resp.GetIpCidrRange(),
+			}
+			for _, secondary := range resp.GetSecondaryIpRanges() {
+				subnet.secondaryRanges = append(subnet.secondaryRanges, secondary.GetIpCidrRange())
+			}
+			resources = append(resources, subnet)
 		}
 	}
 	return resources, nil
@@ -94,8 +116,17 @@ func (x *Subnet) Remove(project *gcputil.Project, client gcputil.GCPClient) (err
 	return nil
 }
 
-func (x *Subnet) GetOperationError(ctx context.Context) error {
-	return getComputeOperationError(ctx, x.operation)
+func (x *Subnet) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	subnetworksClient := client.(*compute.SubnetworksClient)
+
+	req := &computepb.GetSubnetworkRequest{
+		Project:    project.Name,
+		Region:     x.region,
+		Subnetwork: x.name,
+	}
+	_, err := subnetworksClient.Get(ctx, req)
+
+	return existence(err)
 }
 
 func (x *Subnet) String() string {
@@ -107,6 +138,8 @@ func (x *Subnet) Properties() types.Properties {
 	properties.Set("Name", x.name)
 	properties.Set("Network", x.network)
 	properties.Set("CreationDate", x.creationDate)
+	properties.Set("IPCIDRRange", x.ipCIDRRange)
+	properties.Set("SecondaryRanges", strings.Join(x.secondaryRanges, ","))
 
 	return properties
 }
@@ -1,6 +1,9 @@
 package resources
 
-import "strings"
+import (
+	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
+	log "github.com/sirupsen/logrus"
+)
 
 func UnPtrBool(ptr *bool, def bool) bool {
 	if ptr == nil {
@@ -28,11 +31,34 @@ func EqualStringPtr(v1, v2 *string) bool {
 	return *v1 == *v2
 }
 
+// ZoneInRegionList checks whether a zone belongs to the locations. The zone
+// may also be the key of an aggregated list, like zones/us-east1-b or
+// regions/us-east1.
 func ZoneInRegionList(zone string, regions []string) bool {
-	for _, region := range regions {
-		if strings.Contains(zone, region) {
-			return true
-		}
+	return gcputil.ZoneInLocations(zone, regions)
+}
+
+// existence converts the error of a GET request into the result of
+// Getter.Exists.
+func existence(err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+	if gcputil.IsNotFound(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+// inStorageLocations checks whether a resource of Cloud Storage or BigQuery
+// is in the locations of the project. They are only scoped to the locations
+// with the feature flag scope-storage-locations, since their services are
+// global. Skipped resources are logged, so they do not disappear silently.
+func inStorageLocations(project *gcputil.Project, resourceType, name, location string) bool {
+	if !project.ScopeStorageLocations || gcputil.LocationInList(location, project.Locations) {
+		return true
 	}
+
+	log.Infof("Skipping %s %s, because its location %s is not configured.", resourceType, name, location)
 	return false
 }
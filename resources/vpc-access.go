@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"path"
-	"strings"
 
 	vpcaccess "cloud.google.com/go/vpcaccess/apiv1"
 	"cloud.google.com/go/vpcaccess/apiv1/vpcaccesspb"
@@ -23,7 +22,11 @@ type VpcAccess struct {
 }
 
 func init() {
-	register(ResourceTypeVpcAccess, GetVpcAccessClient, ListVpcAccess)
+	register(ResourceTypeVpcAccess, GetVpcAccessClient, ListVpcAccess,
+		withAPI(APIVPCAccess),
+		dependsOn(ResourceTypeCloudRunService, ResourceTypeCloudRunJob, ResourceTypeFunction),
+		withPrototype(&VpcAccess{}),
+	)
 }
 
 func GetVpcAccessClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -31,9 +34,13 @@ func GetVpcAccessClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := vpcaccess.NewClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APIVPCAccess, gcputil.TransportGRPC)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create cloud run client: %v", err)
+		return nil, err
+	}
+	client, err := vpcaccess.NewClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloud run client: %w", err)
 	}
 	project.AddClient(ResourceTypeVpcAccess, client)
 	return client, nil
@@ -45,8 +52,8 @@ func ListVpcAccess(project *gcputil.Project, client gcputil.GCPClient) ([]Resour
 	resources := make([]Resource, 0)
 	var req *vpcaccesspb.ListConnectorsRequest
 	for _, location := range project.Locations {
-		// global isn't a valid location for VPC access
-		if strings.ToLower(location) == "global" {
+		// only regions are valid locations for VPC access
+		if !gcputil.IsRegion(location) {
 			continue
 		}
 		req = &vpcaccesspb.ListConnectorsRequest{
@@ -61,7 +68,7 @@ func ListVpcAccess(project *gcputil.Project, client gcputil.GCPClient) ([]Resour
 			}
 
 			if err != nil {
-				return nil, fmt.Errorf("failed to list subnetworks: %v", err)
+				return nil, fmt.Errorf("failed to list subnetworks: %w", err)
 			}
 			resources = append(resources, &VpcAccess{
 				name:    path.Base(resp.GetName()),
@@ -95,6 +102,29 @@ func (x *VpcAccess) GetOperationError(ctx context.Context) (err error) {
 	return err
 }
 
+func (x *VpcAccess) OperationName() string {
+	if x.operation == nil {
+		return ""
+	}
+	return x.operation.Name()
+}
+
+func (x *VpcAccess) ResumeOperation(project *gcputil.Project, client gcputil.GCPClient, name string) error {
+	x.operation = client.(*vpcaccess.Client).DeleteConnectorOperation(name)
+	return nil
+}
+
+func (x *VpcAccess) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	vpcAccessClient := client.(*vpcaccess.Client)
+
+	req := &vpcaccesspb.GetConnectorRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s/connectors/%s", project.Name, x.region, x.name),
+	}
+	_, err := vpcAccessClient.GetConnector(ctx, req)
+
+	return existence(err)
+}
+
 func (x *VpcAccess) String() string {
 	return x.name
 }
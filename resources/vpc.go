@@ -3,7 +3,6 @@ package resources
 import (
 	"context"
 	"fmt"
-	"net/http"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"github.com/dshelley66/gcp-nuke/pkg/gcputil"
@@ -17,13 +16,27 @@ const ResourceTypeVPC = "VPC"
 type Vpc struct {
 	name         string
 	creationDate string
-	operation    *compute.Operation
+	computeOperation
 }
 
 var noDefaultNetworkFilter = "name != default"
 
 func init() {
-	register(ResourceTypeVPC, GetNetworkClient, ListVpcs)
+	register(ResourceTypeVPC, GetNetworkClient, ListVpcs,
+		withAPI(APICompute),
+		dependsOn(
+			ResourceTypeSubnet,
+			ResourceTypeFirewall,
+			ResourceTypeRoute,
+			ResourceTypeRouter,
+			ResourceTypeGlobalIPAddress,
+			ResourceTypeVpcAccess,
+			ResourceTypeGlobalNetworkEndpointGroup,
+			ResourceTypeRegionalNetworkEndpointGroup,
+			ResourceTypeZonalNetworkEndpointGroup,
+		),
+		withPrototype(&Vpc{}),
+	)
 }
 
 func GetNetworkClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -31,9 +44,13 @@ func GetNetworkClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := compute.NewNetworksRESTClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APICompute, gcputil.TransportREST)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create network client: %v", err)
+		return nil, err
+	}
+	client, err := compute.NewNetworksRESTClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create network client: %w", err)
 	}
 	project.AddClient(ResourceTypeVPC, client)
 	return client, nil
@@ -56,7 +73,7 @@ func ListVpcs(project *gcputil.Project, client gcputil.GCPClient) ([]Resource, e
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list networks: %v", err)
+			return nil, fmt.Errorf("failed to list networks: %w", err)
 		}
 		resources = append(resources, &Vpc{
 			name:         UnPtrString(resp.Name, ""),
@@ -82,23 +99,16 @@ func (x *Vpc) Remove(project *gcputil.Project, client gcputil.GCPClient) (err er
 	return nil
 }
 
-func (x *Vpc) GetOperationError(ctx context.Context) error {
-	return getComputeOperationError(ctx, x.operation)
-}
+func (x *Vpc) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	networksClient := client.(*compute.NetworksClient)
 
-func getComputeOperationError(ctx context.Context, op *compute.Operation) error {
-	if op != nil {
-		if err := op.Poll(ctx); err == nil {
-			if op.Done() {
-				if op.Proto().GetHttpErrorStatusCode() != http.StatusOK {
-					return fmt.Errorf("Delete error on '%s': %s", op.Proto().GetTargetLink(), op.Proto().GetHttpErrorMessage())
-				}
-			}
-		} else {
-			return err
-		}
+	req := &computepb.GetNetworkRequest{
+		Network: x.name,
+		Project: project.Name,
 	}
-	return nil
+	_, err := networksClient.Get(ctx, req)
+
+	return existence(err)
 }
 
 func (x *Vpc) String() string {
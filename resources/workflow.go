@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"path"
-	"strings"
 
 	workflows "cloud.google.com/go/workflows/apiv1"
 	workflowspb "cloud.google.com/go/workflows/apiv1/workflowspb"
@@ -23,7 +22,10 @@ type Workflow struct {
 }
 
 func init() {
-	register(ResourceTypeWorkflow, GetWorkflowsClient, ListWorkflows)
+	register(ResourceTypeWorkflow, GetWorkflowsClient, ListWorkflows,
+		withAPI(APIWorkflows),
+		withPrototype(&Workflow{}),
+	)
 }
 
 func GetWorkflowsClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -31,9 +33,13 @@ func GetWorkflowsClient(project *gcputil.Project) (gcputil.GCPClient, error) {
 		return client, nil
 	}
 
-	client, err := workflows.NewClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APIWorkflows, gcputil.TransportGRPC)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create workflows client: %v", err)
+		return nil, err
+	}
+	client, err := workflows.NewClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workflows client: %w", err)
 	}
 	project.AddClient(ResourceTypeWorkflow, client)
 	return client, nil
@@ -45,8 +51,8 @@ func ListWorkflows(project *gcputil.Project, client gcputil.GCPClient) ([]Resour
 	resources := make([]Resource, 0)
 
 	for _, location := range project.Locations {
-		// global isn't a valid location for Workflows
-		if strings.ToLower(location) == "global" {
+		// only regions are valid locations for Workflows
+		if !gcputil.IsRegion(location) {
 			continue
 		}
 		req := &workflowspb.ListWorkflowsRequest{
@@ -60,7 +66,7 @@ func ListWorkflows(project *gcputil.Project, client gcputil.GCPClient) ([]Resour
 			}
 
 			if err != nil {
-				return nil, fmt.Errorf("failed to list workflows: %v", err)
+				return nil, fmt.Errorf("failed to list workflows: %w", err)
 			}
 			resources = append(resources, &Workflow{
 				name:     path.Base(workflows.Name),
@@ -90,6 +96,17 @@ func (x *Workflow) GetOperationError(_ context.Context) error {
 	return nil
 }
 
+func (x *Workflow) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	workflowsClient := client.(*workflows.Client)
+
+	req := &workflowspb.GetWorkflowRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s/workflows/%s", x.project, x.location, x.name),
+	}
+	_, err := workflowsClient.GetWorkflow(ctx, req)
+
+	return existence(err)
+}
+
 func (x *Workflow) String() string {
 	return x.name
 }
@@ -21,11 +21,14 @@ type ZonalNetworkEndpointGroup struct {
 	zone         string
 	negType      string
 	creationDate string
-	operation    *compute.Operation
+	computeOperation
 }
 
 func init() {
-	register(ResourceTypeZonalNetworkEndpointGroup, GetZonalNetworkEndpointGroupClient, ListZonalNetworkEndpointGroups)
+	register(ResourceTypeZonalNetworkEndpointGroup, GetZonalNetworkEndpointGroupClient, ListZonalNetworkEndpointGroups,
+		withAPI(APICompute),
+		withPrototype(&ZonalNetworkEndpointGroup{}),
+	)
 }
 
 func GetZonalNetworkEndpointGroupClient(project *gcputil.Project) (gcputil.GCPClient, error) {
@@ -33,9 +36,13 @@ func GetZonalNetworkEndpointGroupClient(project *gcputil.Project) (gcputil.GCPCl
 		return client, nil
 	}
 
-	client, err := compute.NewNetworkEndpointGroupsRESTClient(project.GetContext(), project.Creds.GetNewClientOptions()...)
+	options, err := project.ClientOptions(APICompute, gcputil.TransportREST)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create network endpoint group client: %v", err)
+		return nil, err
+	}
+	client, err := compute.NewNetworkEndpointGroupsRESTClient(project.GetContext(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create network endpoint group client: %w", err)
 	}
 	project.AddClient(ResourceTypeZonalNetworkEndpointGroup, client)
 	return client, nil
@@ -59,7 +66,7 @@ func ListZonalNetworkEndpointGroups(project *gcputil.Project, client gcputil.GCP
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list network endpiont groups: %v", err)
+			return nil, fmt.Errorf("failed to list network endpiont groups: %w", err)
 		}
 
 		if ZoneInRegionList(resp.Key, project.Locations) {
@@ -93,8 +100,17 @@ func (x *ZonalNetworkEndpointGroup) Remove(project *gcputil.Project, client gcpu
 	return nil
 }
 
-func (x *ZonalNetworkEndpointGroup) GetOperationError(ctx context.Context) error {
-	return getComputeOperationError(ctx, x.operation)
+func (x *ZonalNetworkEndpointGroup) Exists(ctx context.Context, project *gcputil.Project, client gcputil.GCPClient) (bool, error) {
+	negClient := client.(*compute.NetworkEndpointGroupsClient)
+
+	req := &computepb.GetNetworkEndpointGroupRequest{
+		Project:              project.Name,
+		Zone:                 x.zone,
+		NetworkEndpointGroup: x.name,
+	}
+	_, err := negClient.Get(ctx, req)
+
+	return existence(err)
 }
 
 func (x *ZonalNetworkEndpointGroup) String() string {